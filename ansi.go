@@ -0,0 +1,329 @@
+package cli
+
+// TokenKind represents the kind of tokens produced by a Tokenizer.
+type TokenKind int
+
+const (
+	// TextToken is a sequence of printable text (including control characters
+	// other than ESC).
+	TextToken TokenKind = iota
+
+	// EscapeToken is an escape sequence that isn't one of the other kinds,
+	// like ESC 7 or ESC ( B. A lone ESC character is also an escape token.
+	EscapeToken
+
+	// CSIToken is a control sequence introduced by ESC [, which covers styles
+	// (SGR), cursor movements, erase codes, etc...
+	CSIToken
+
+	// OSCToken is an operating system command introduced by ESC ] and
+	// terminated by BEL or ST, hyperlinks and window titles are OSC tokens.
+	OSCToken
+
+	// DCSToken is a device control string introduced by ESC P and terminated
+	// by ST.
+	DCSToken
+
+	// StringToken is a control string introduced by ESC X (SOS), ESC ^ (PM)
+	// or ESC _ (APC) and terminated by ST.
+	StringToken
+
+	// SS2Token is the single shift two sequence, ESC N.
+	SS2Token
+
+	// SS3Token is the single shift three sequence, ESC O.
+	SS3Token
+)
+
+const (
+	esc = 0x1B
+	bel = 0x07
+	can = 0x18
+	sub = 0x1A
+)
+
+// Token is a piece of text or an escape sequence extracted by a Tokenizer.
+type Token struct {
+	Kind  TokenKind
+	Bytes []byte
+}
+
+// Params returns the parameter bytes of a control sequence, for example
+// "1;32" in ESC [ 1 ; 3 2 m.
+func (t Token) Params() []byte {
+	if t.Kind != CSIToken {
+		return nil
+	}
+	i, _, _ := t.csi()
+	return t.Bytes[2:i]
+}
+
+// Intermediates returns the intermediate bytes of a control sequence or an
+// escape sequence.
+func (t Token) Intermediates() []byte {
+	switch t.Kind {
+	case CSIToken:
+		i, j, _ := t.csi()
+		return t.Bytes[i:j]
+	case EscapeToken:
+		if n := len(t.Bytes); n > 2 {
+			return t.Bytes[1 : n-1]
+		}
+	}
+	return nil
+}
+
+// Final returns the final byte of a control sequence or an escape sequence,
+// or zero if the sequence was truncated.
+func (t Token) Final() byte {
+	switch t.Kind {
+	case CSIToken:
+		if _, j, k := t.csi(); k != j {
+			return t.Bytes[j]
+		}
+	case EscapeToken:
+		if n := len(t.Bytes); n > 1 {
+			return t.Bytes[n-1]
+		}
+	}
+	return 0
+}
+
+// Data returns the content of OSC, DCS and control string tokens, without
+// the introducer and terminator.
+func (t Token) Data() []byte {
+	switch t.Kind {
+	case OSCToken, DCSToken, StringToken:
+	default:
+		return nil
+	}
+
+	b := t.Bytes[2:]
+
+	switch n := len(b); {
+	case n >= 2 && b[n-2] == esc && b[n-1] == '\\':
+		b = b[:n-2]
+	case n >= 1 && (b[n-1] == bel || b[n-1] == can || b[n-1] == sub):
+		b = b[:n-1]
+	}
+
+	return b
+}
+
+func (t Token) csi() (params int, intermediates int, final int) {
+	b := t.Bytes
+	i := 2
+
+	for i < len(b) && isParamByte(b[i]) {
+		i++
+	}
+
+	j := i
+
+	for j < len(b) && isIntermediateByte(b[j]) {
+		j++
+	}
+
+	k := j
+
+	if k < len(b) && isFinalByte(b[k]) {
+		k++
+	}
+
+	return i, j, k
+}
+
+// Tokenizer splits a byte sequence into text and ECMA-48 escape sequences.
+//
+// The tokenizer never fails, malformed or truncated sequences are returned
+// as tokens holding the bytes that were consumed.
+type Tokenizer struct {
+	b []byte
+	t Token
+}
+
+// NewTokenizer returns a Tokenizer reading tokens from b.
+func NewTokenizer(b []byte) *Tokenizer {
+	return &Tokenizer{b: b}
+}
+
+// Next advances the tokenizer to the next token, returning false when the end
+// of the input was reached.
+func (t *Tokenizer) Next() bool {
+	if len(t.b) == 0 {
+		t.t = Token{}
+		return false
+	}
+
+	kind, n := scanToken(t.b)
+	t.t = Token{Kind: kind, Bytes: t.b[:n:n]}
+	t.b = t.b[n:]
+	return true
+}
+
+// Token returns the token that the last call to Next advanced to.
+func (t *Tokenizer) Token() Token {
+	return t.t
+}
+
+// ForEachTokenInString is like ForEachToken but takes a string as argument.
+func ForEachTokenInString(s string, do func(Token)) {
+	ForEachToken([]byte(s), do)
+}
+
+// ForEachToken calls do with each token found in b.
+func ForEachToken(b []byte, do func(Token)) {
+	for t := NewTokenizer(b); t.Next(); {
+		do(t.Token())
+	}
+}
+
+func scanToken(b []byte) (TokenKind, int) {
+	if b[0] != esc {
+		i := 1
+
+		for i < len(b) && b[i] != esc {
+			i++
+		}
+
+		return TextToken, i
+	}
+
+	if len(b) == 1 {
+		return EscapeToken, 1
+	}
+
+	switch c := b[1]; {
+	case c == '[':
+		return CSIToken, scanControlSequence(b, 2)
+	case c == ']':
+		return OSCToken, scanControlString(b, 2, true)
+	case c == 'P':
+		return DCSToken, scanControlString(b, 2, false)
+	case c == 'X', c == '^', c == '_':
+		return StringToken, scanControlString(b, 2, false)
+	case c == 'N':
+		return SS2Token, 2
+	case c == 'O':
+		return SS3Token, 2
+	case isIntermediateByte(c):
+		return EscapeToken, scanEscapeSequence(b, 1)
+	case c >= 0x30 && c <= 0x7E:
+		return EscapeToken, 2
+	default:
+		return EscapeToken, 1
+	}
+}
+
+func scanControlSequence(b []byte, i int) int {
+	for i < len(b) && isParamByte(b[i]) {
+		i++
+	}
+
+	for i < len(b) && isIntermediateByte(b[i]) {
+		i++
+	}
+
+	if i < len(b) && isFinalByte(b[i]) {
+		i++
+	}
+
+	return i
+}
+
+func scanControlString(b []byte, i int, acceptBEL bool) int {
+	for ; i < len(b); i++ {
+		switch b[i] {
+		case bel:
+			if acceptBEL {
+				return i + 1
+			}
+		case can, sub:
+			return i + 1
+		case esc:
+			if (i+1) < len(b) && b[i+1] == '\\' {
+				return i + 2
+			}
+			// Any other escape sequence interrupts the control string.
+			return i
+		}
+	}
+	return i
+}
+
+func scanEscapeSequence(b []byte, i int) int {
+	for i < len(b) && isIntermediateByte(b[i]) {
+		i++
+	}
+
+	if i < len(b) && b[i] >= 0x30 && b[i] <= 0x7E {
+		i++
+	}
+
+	return i
+}
+
+// truncatedSequence returns the position of the escape sequence which is cut at
+// the end of b, or len(b) if b doesn't end in the middle of a sequence. A lone
+// ESC at the end of b is treated as the start of a sequence.
+func truncatedSequence(b []byte) int {
+	prev, prevKind := -1, TextToken
+
+	for i := 0; i < len(b); {
+		kind, n := scanToken(b[i:])
+		tok := Token{Kind: kind, Bytes: b[i : i+n]}
+
+		if i+n == len(b) && truncated(tok) {
+			// A control string interrupted by the ESC at the end of b may
+			// be followed by the rest of its terminator.
+			if n == 1 && prev >= 0 && isControlString(prevKind) && truncated(Token{Kind: prevKind, Bytes: b[prev:i]}) {
+				return prev
+			}
+			return i
+		}
+
+		prev, prevKind = i, kind
+		i += n
+	}
+
+	return len(b)
+}
+
+// truncated returns true if t is an escape sequence which is missing its final
+// byte or its terminator.
+func truncated(t Token) bool {
+	b := t.Bytes
+
+	switch t.Kind {
+	case EscapeToken:
+		return len(b) == 1 || isIntermediateByte(b[len(b)-1])
+	case CSIToken:
+		return t.Final() == 0
+	case OSCToken, DCSToken, StringToken:
+		switch c := b[len(b)-1]; {
+		case c == bel && t.Kind == OSCToken, c == can, c == sub:
+			return false
+		case c == '\\' && len(b) >= 4 && b[len(b)-2] == esc:
+			return false
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+func isControlString(kind TokenKind) bool {
+	return kind == OSCToken || kind == DCSToken || kind == StringToken
+}
+
+func isParamByte(c byte) bool {
+	return c >= 0x30 && c <= 0x3F
+}
+
+func isIntermediateByte(c byte) bool {
+	return c >= 0x20 && c <= 0x2F
+}
+
+func isFinalByte(c byte) bool {
+	return c >= 0x40 && c <= 0x7E
+}
@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenizer(t *testing.T) {
+	tests := []struct {
+		in     string
+		tokens []Token
+	}{
+		{
+			in:     "",
+			tokens: nil,
+		},
+		{
+			in: "Hello World!",
+			tokens: []Token{
+				{TextToken, []byte("Hello World!")},
+			},
+		},
+		{
+			in: "\033[1;32mHello\033[0m",
+			tokens: []Token{
+				{CSIToken, []byte("\033[1;32m")},
+				{TextToken, []byte("Hello")},
+				{CSIToken, []byte("\033[0m")},
+			},
+		},
+		{
+			in: "\033[?25l\033[3;1H",
+			tokens: []Token{
+				{CSIToken, []byte("\033[?25l")},
+				{CSIToken, []byte("\033[3;1H")},
+			},
+		},
+		{
+			in: "\033]8;;https://example.com\007link\033]8;;\033\\",
+			tokens: []Token{
+				{OSCToken, []byte("\033]8;;https://example.com\007")},
+				{TextToken, []byte("link")},
+				{OSCToken, []byte("\033]8;;\033\\")},
+			},
+		},
+		{
+			in: "\033P1$r\033\\\033_APC\033\\",
+			tokens: []Token{
+				{DCSToken, []byte("\033P1$r\033\\")},
+				{StringToken, []byte("\033_APC\033\\")},
+			},
+		},
+		{
+			in: "\033NA\033OB",
+			tokens: []Token{
+				{SS2Token, []byte("\033N")},
+				{TextToken, []byte("A")},
+				{SS3Token, []byte("\033O")},
+				{TextToken, []byte("B")},
+			},
+		},
+		{
+			in: "\033(B\0337\033",
+			tokens: []Token{
+				{EscapeToken, []byte("\033(B")},
+				{EscapeToken, []byte("\0337")},
+				{EscapeToken, []byte("\033")},
+			},
+		},
+		{
+			in: "\033\033[m",
+			tokens: []Token{
+				{EscapeToken, []byte("\033")},
+				{CSIToken, []byte("\033[m")},
+			},
+		},
+		{
+			in: "\033]0;title\033[1m",
+			tokens: []Token{
+				{OSCToken, []byte("\033]0;title")},
+				{CSIToken, []byte("\033[1m")},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		var tokens []Token
+
+		ForEachTokenInString(test.in, func(t Token) {
+			tokens = append(tokens, t)
+		})
+
+		if !reflect.DeepEqual(tokens, test.tokens) {
+			t.Errorf("%q:\n- expected: %v\n- found:    %v", test.in, test.tokens, tokens)
+		}
+	}
+}
+
+func TestTokenFields(t *testing.T) {
+	tok := Token{CSIToken, []byte("\033[1;32 q")}
+
+	if s := string(tok.Params()); s != "1;32" {
+		t.Errorf("invalid params: %q", s)
+	}
+
+	if s := string(tok.Intermediates()); s != " " {
+		t.Errorf("invalid intermediates: %q", s)
+	}
+
+	if c := tok.Final(); c != 'q' {
+		t.Errorf("invalid final byte: %q", c)
+	}
+
+	tok = Token{OSCToken, []byte("\033]8;;https://example.com\007")}
+
+	if s := string(tok.Data()); s != "8;;https://example.com" {
+		t.Errorf("invalid data: %q", s)
+	}
+}
@@ -15,7 +15,7 @@ func RenderCellLeftAlign(w io.Writer, cell string, width int) (err error) {
 		return
 	}
 
-	if n := RuneCountInString(cell); n < width {
+	if n := DisplayWidthInString(cell); n < width {
 		err = writeSpaces(w, width-n)
 	}
 
@@ -23,7 +23,7 @@ func RenderCellLeftAlign(w io.Writer, cell string, width int) (err error) {
 }
 
 func RenderCellRightAlign(w io.Writer, cell string, width int) (err error) {
-	if n := RuneCountInString(cell); n < width {
+	if n := DisplayWidthInString(cell); n < width {
 		err = writeSpaces(w, width-n)
 	}
 
@@ -35,7 +35,7 @@ func RenderCellRightAlign(w io.Writer, cell string, width int) (err error) {
 }
 
 func RenderCellCentered(w io.Writer, cell string, width int) (err error) {
-	n := RuneCountInString(cell)
+	n := DisplayWidthInString(cell)
 	l := 0
 	r := 0
 
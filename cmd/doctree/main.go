@@ -0,0 +1,97 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/achille-roussel/cli"
+	"github.com/achille-roussel/cli/tree"
+	"github.com/achille-roussel/cli/tree/yamltree"
+)
+
+type decoder interface {
+	Decode() (cli.TreeView, error)
+}
+
+func main() {
+	var format string
+	var paths []string
+
+	flag.StringVar(&format, "f", "", "format of the documents, json or yaml (default guessed from the file extension, json for stdin)")
+	flag.Parse()
+
+	if paths = flag.Args(); len(paths) == 0 {
+		paths = []string{"-"}
+	}
+
+	cli.Init()
+	defer cli.Close()
+
+	status := 0
+
+	for _, path := range paths {
+		if err := render(path, format); err != nil {
+			cli.Printf("%s: %s\n", path, err)
+			status = 1
+		}
+	}
+
+	if status != 0 {
+		cli.Close()
+		os.Exit(status)
+	}
+}
+
+func render(path string, format string) (err error) {
+	var r io.Reader = os.Stdin
+
+	if path != "-" {
+		var f *os.File
+
+		if f, err = os.Open(path); err != nil {
+			return
+		}
+
+		defer f.Close()
+		r = f
+	}
+
+	if len(format) == 0 {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".yaml", ".yml":
+			format = "yaml"
+		default:
+			format = "json"
+		}
+	}
+
+	var d decoder
+
+	switch format {
+	case "json":
+		d = tree.NewJSONDecoder(r)
+	case "yaml":
+		d = yamltree.NewDecoder(r)
+	default:
+		return fmt.Errorf("unsupported document format: %s", format)
+	}
+
+	for {
+		var doc cli.TreeView
+
+		if doc, err = d.Decode(); err != nil {
+			if err == io.EOF {
+				err = nil
+			}
+			return
+		}
+
+		if err = cli.RenderTreeView(cli.Output, doc); err != nil {
+			return
+		}
+	}
+}
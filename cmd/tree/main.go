@@ -3,6 +3,7 @@ package main
 import (
 	"flag"
 	"os"
+	"strings"
 
 	"github.com/achille-roussel/cli"
 	"github.com/achille-roussel/cli/tree"
@@ -12,23 +13,98 @@ func main() {
 	var config = tree.DefaultPathConfig
 	var paths []string
 
+	var options cli.TreeOptions
+	var size, mode, date, noreport bool
+	var include, exclude, order string
+	var version, mtime bool
+	var stats tree.PathStats
+
 	flag.BoolVar(&config.ShowHidden, "a", false, "show hidden files")
+	flag.BoolVar(&size, "s", false, "print the size of each file")
+	flag.BoolVar(&config.HumanizeSizes, "h", false, "print sizes in a human readable format")
+	flag.BoolVar(&config.DirSizes, "du", false, "print the size of directories as the sum of the sizes of their files")
+	flag.BoolVar(&mode, "p", false, "print the permissions of each file")
+	flag.BoolVar(&date, "D", false, "print the modification time of each file")
+	flag.StringVar(&include, "P", "", "list only the files matching the pattern, alternatives are separated by '|'")
+	flag.StringVar(&exclude, "I", "", "do not list the files matching the pattern, alternatives are separated by '|'")
+	flag.BoolVar(&config.GitIgnore, "gitignore", false, "do not list the files ignored by .gitignore files")
+	flag.BoolVar(&config.DirsOnly, "d", false, "list directories only")
+	flag.IntVar(&options.MaxDepth, "L", 0, "max depth of the directory tree")
+	flag.BoolVar(&config.FollowSymlinks, "l", false, "follow symbolic links to directories")
+	flag.BoolVar(&config.PruneEmpty, "prune", false, "do not list empty directories")
+	flag.BoolVar(&noreport, "noreport", false, "do not print the count of directories and files")
+	flag.StringVar(&order, "sort", "name", "sort files by name, version, size, mtime or extension")
+	flag.BoolVar(&version, "v", false, "sort files by version, same as -sort=name")
+	flag.BoolVar(&mtime, "t", false, "sort files by modification time, same as -sort=mtime")
+	flag.BoolVar(&config.DirsFirst, "dirsfirst", false, "list directories before files")
+	flag.BoolVar(&config.Reverse, "r", false, "sort files in reverse order")
 	flag.Parse()
 
+	cli.Init()
+	defer cli.Close()
+
+	switch {
+	case version:
+		order = "version"
+	case mtime:
+		order = "mtime"
+	}
+
+	if by, err := tree.ParsePathSort(order); err != nil {
+		cli.Printf("%s\n", err)
+		cli.Close()
+		os.Exit(2)
+	} else {
+		config.SortBy = by
+	}
+
+	if len(include) != 0 {
+		config.Include = strings.Split(include, "|")
+	}
+
+	if len(exclude) != 0 {
+		config.Exclude = strings.Split(exclude, "|")
+	}
+
+	if !noreport {
+		// The filter sees every node rendered under the roots.
+		options.Filter = func(node cli.TreeView) bool {
+			stats.Count(node)
+			return true
+		}
+	}
+
+	if size || config.HumanizeSizes || config.DirSizes {
+		config.Columns = append(config.Columns, tree.SizeColumn)
+	}
+
+	if mode {
+		config.Columns = append(config.Columns, tree.ModeColumn)
+	}
+
+	if date {
+		config.Columns = append(config.Columns, tree.ModTimeColumn)
+	}
+
 	if paths = flag.Args(); len(paths) == 0 {
 		paths = []string{"."}
 	}
 
-	cli.Init()
-	defer cli.Close()
-
 	for _, path := range paths {
 		if dir, err := os.Open(path); err != nil {
 			cli.Printf("%s: %s\n", path, err)
 		} else {
 			info, _ := dir.Stat()
 			dir.Close()
-			cli.RenderTreeView(cli.Output, tree.PathWithConfig(info, path, config))
+			if len(config.Columns) == 0 {
+				cli.RenderTreeViewWithOptions(cli.Output, tree.PathWithConfig(info, path, config), options)
+			} else {
+				cli.RenderTreeTableViewWithOptions(cli.Output, tree.PathWithConfig(info, path, config), options, config.ColumnSpecs()...)
+			}
 		}
 	}
+
+	if !noreport {
+		cli.Printf("\n%s\n", stats)
+	}
 }
@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	extendedFG = 38
+	extendedBG = 48
+	color256   = 5
+	colorRGB   = 2
+)
+
+// Color256 returns a style setting the foreground to the color n of the 256
+// colors palette.
+func Color256(n uint8) StyleSet {
+	return StyleSet{extendedFG, color256, int(n)}
+}
+
+// BgColor256 returns a style setting the background to the color n of the 256
+// colors palette.
+func BgColor256(n uint8) StyleSet {
+	return StyleSet{extendedBG, color256, int(n)}
+}
+
+// RGB returns a style setting the foreground to a 24-bit color.
+func RGB(r, g, b uint8) StyleSet {
+	return StyleSet{extendedFG, colorRGB, int(r), int(g), int(b)}
+}
+
+// BgRGB returns a style setting the background to a 24-bit color.
+func BgRGB(r, g, b uint8) StyleSet {
+	return StyleSet{extendedBG, colorRGB, int(r), int(g), int(b)}
+}
+
+// Hex parses a color in hexadecimal notation ("#ff8000", "ff8000" or "#f80")
+// and returns a style setting the foreground to that color.
+func Hex(color string) (StyleSet, error) {
+	r, g, b, err := parseHexColor(color)
+	if err != nil {
+		return nil, err
+	}
+	return RGB(r, g, b), nil
+}
+
+// BgHex is like Hex but returns a style setting the background color.
+func BgHex(color string) (StyleSet, error) {
+	r, g, b, err := parseHexColor(color)
+	if err != nil {
+		return nil, err
+	}
+	return BgRGB(r, g, b), nil
+}
+
+func parseHexColor(color string) (r, g, b uint8, err error) {
+	s := strings.TrimPrefix(color, "#")
+
+	switch len(s) {
+	case 3:
+		s = string([]byte{s[0], s[0], s[1], s[1], s[2], s[2]})
+	case 6:
+	default:
+		err = fmt.Errorf("cli: invalid hex color %q", color)
+		return
+	}
+
+	var v uint64
+
+	if v, err = strconv.ParseUint(s, 16, 32); err != nil {
+		err = fmt.Errorf("cli: invalid hex color %q", color)
+		return
+	}
+
+	r, g, b = uint8(v>>16), uint8(v>>8), uint8(v)
+	return
+}
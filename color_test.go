@@ -0,0 +1,58 @@
+package cli
+
+import "testing"
+
+func TestExtendedColors(t *testing.T) {
+	tests := []struct {
+		name  string
+		style StyleSet
+		seq   string
+	}{
+		{"default-fg", Default, "\033[39m"},
+		{"default-bg", DefaultBG, "\033[49m"},
+		{"bright-red-fg", BrightRed, "\033[91m"},
+		{"bright-red-bg", BrightRedBG, "\033[101m"},
+		{"color256-fg", Color256(208), "\033[38;5;208m"},
+		{"color256-bg", BgColor256(17), "\033[48;5;17m"},
+		{"rgb-fg", RGB(255, 128, 0), "\033[38;2;255;128;0m"},
+		{"rgb-bg", BgRGB(0, 64, 255), "\033[48;2;0;64;255m"},
+	}
+
+	for _, test := range tests {
+		if s := test.style.String(); s != test.seq {
+			t.Errorf("%s: expected %q but found %q", test.name, test.seq, s)
+		}
+
+		if s := test.style.S("Hello World!"); s != test.seq+"Hello World!\033[0m" {
+			t.Errorf("%s: invalid styled string %q", test.name, s)
+		}
+	}
+}
+
+func TestHex(t *testing.T) {
+	tests := []struct {
+		in  string
+		out StyleSet
+	}{
+		{"#ff8000", RGB(255, 128, 0)},
+		{"FF8000", RGB(255, 128, 0)},
+		{"#f80", RGB(255, 136, 0)},
+		{"#000000", RGB(0, 0, 0)},
+	}
+
+	for _, test := range tests {
+		style, err := Hex(test.in)
+
+		if err != nil {
+			t.Errorf("%s: %s", test.in, err)
+		} else if style.String() != test.out.String() {
+			t.Errorf("%s: expected %q but found %q", test.in, test.out.String(), style.String())
+		}
+	}
+
+	for _, s := range []string{"", "#", "#ff80", "#gg0000", "#ff00000"} {
+		if _, err := BgHex(s); err == nil {
+			t.Errorf("%q: expected an error", s)
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package cli
 
-import "io"
+import (
+	"io"
+	"strings"
+)
 
 type column string
 
@@ -30,6 +33,59 @@ func (c column) string() string {
 	return s
 }
 
+// groups returns the names of the groups that the column belongs to, from the
+// outermost to the innermost. Groups are written in brackets before the name
+// of the column in column specs, for example "[LATENCY]P50".
+func (c column) groups() []string {
+	groups, _ := c.split()
+	return groups
+}
+
+// name returns the name of the column without the groups it belongs to.
+func (c column) name() string {
+	_, name := c.split()
+	return name
+}
+
+func (c column) split() (groups []string, name string) {
+	name = c.string()
+
+	for len(name) != 0 && name[0] == '[' {
+		i := strings.IndexByte(name, ']')
+
+		if i < 0 {
+			break
+		}
+
+		groups, name = append(groups, name[1:i]), name[i+1:]
+	}
+
+	if len(name) == 0 {
+		// A spec made of brackets only, like "[ms]", is a column name.
+		return nil, c.string()
+	}
+
+	return
+}
+
 func RenderColumn(w io.Writer, col string, width int) (err error) {
-	return RenderCell(w, column(col).string(), width, column(col).alignment())
+	return RenderCell(w, column(col).name(), width, column(col).alignment())
+}
+
+// findColumn returns the index of the column of t with the given name, or -1
+// if none was found. Names are compared without alignment markers or styles,
+// ignoring case. Grouped columns match either their full "[GROUP]NAME" spec or
+// their name alone.
+func findColumn(t TableView, name string) int {
+	cols, _ := t.Size()
+
+	for i := 0; i != cols; i++ {
+		c := column(StripStylesInString(t.Column(i)))
+
+		if strings.EqualFold(c.string(), name) || strings.EqualFold(c.name(), name) {
+			return i
+		}
+	}
+
+	return -1
 }
@@ -0,0 +1,100 @@
+// Package compare implements the orderings shared by the sorting of tables and
+// trees.
+package compare
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Ordered returns -1 if less is true, 1 if greater is true, and 0 otherwise.
+func Ordered(less bool, greater bool) int {
+	switch {
+	case less:
+		return -1
+	case greater:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Natural compares two strings in natural order, returning a negative value if
+// a comes first, zero if they are equal, and a positive value if b comes first.
+// Sequences of digits are compared by numeric value, so "file2" sorts before
+// "file10".
+func Natural(a string, b string) int {
+	if c := natural(a, b, false); c != 0 {
+		return c
+	}
+	return strings.Compare(a, b)
+}
+
+// NaturalFold is like Natural but compares letters ignoring case, strings
+// which only differ by case are ordered by Natural.
+func NaturalFold(a string, b string) int {
+	if c := natural(a, b, true); c != 0 {
+		return c
+	}
+	return Natural(a, b)
+}
+
+func natural(a string, b string, fold bool) int {
+	for len(a) != 0 && len(b) != 0 {
+		if isDigit(a[0]) && isDigit(b[0]) {
+			na, nb := digitPrefix(a), digitPrefix(b)
+
+			if c := compareDigits(a[:na], b[:nb]); c != 0 {
+				return c
+			}
+
+			a, b = a[na:], b[nb:]
+			continue
+		}
+
+		ra, za := utf8.DecodeRuneInString(a)
+		rb, zb := utf8.DecodeRuneInString(b)
+
+		if fold {
+			ra, rb = unicode.ToLower(ra), unicode.ToLower(rb)
+		}
+
+		if ra != rb {
+			return Ordered(ra < rb, ra > rb)
+		}
+
+		a, b = a[za:], b[zb:]
+	}
+
+	return Ordered(len(a) < len(b), len(a) > len(b))
+}
+
+func compareDigits(a string, b string) int {
+	ta, tb := strings.TrimLeft(a, "0"), strings.TrimLeft(b, "0")
+
+	if len(ta) != len(tb) {
+		return Ordered(len(ta) < len(tb), len(ta) > len(tb))
+	}
+
+	if c := strings.Compare(ta, tb); c != 0 {
+		return c
+	}
+
+	// Equal values, the one with fewer leading zeros comes first.
+	return Ordered(len(a) < len(b), len(a) > len(b))
+}
+
+func digitPrefix(s string) int {
+	i := 0
+
+	for i < len(s) && isDigit(s[i]) {
+		i++
+	}
+
+	return i
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
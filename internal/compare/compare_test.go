@@ -0,0 +1,31 @@
+package compare
+
+import "testing"
+
+func TestNatural(t *testing.T) {
+	tests := []struct {
+		a, b    string
+		natural int
+		fold    int
+	}{
+		{"a", "a", 0, 0},
+		{"a", "b", -1, -1},
+		{"file2", "file10", -1, -1},
+		{"file2", "File10", 1, -1},
+		{"v1.2", "v1.10", -1, -1},
+		{"01", "1", 1, 1},
+		{"A", "a", -1, -1},
+		{"a", "B", 1, -1},
+		{"ab", "a", 1, 1},
+	}
+
+	for _, test := range tests {
+		if c := Natural(test.a, test.b); c != test.natural {
+			t.Errorf("Natural(%q, %q): expected %d but found %d", test.a, test.b, test.natural, c)
+		}
+
+		if c := NaturalFold(test.a, test.b); c != test.fold {
+			t.Errorf("NaturalFold(%q, %q): expected %d but found %d", test.a, test.b, test.fold, c)
+		}
+	}
+}
@@ -21,6 +21,8 @@ type ReadWriter interface {
 
 	Flush() error
 
+	Size() (width int, height int)
+
 	ReadLine(prompt string) (line string, err error)
 
 	ReadPassword(prompt string) (line string, err error)
@@ -29,6 +31,11 @@ type ReadWriter interface {
 type readWriter struct {
 	Reader
 	Writer
+	ascii bool
+}
+
+func (rw readWriter) asciiOnly() bool {
+	return rw.ascii
 }
 
 func (rw readWriter) Close() (err error) {
@@ -45,6 +52,10 @@ func Flush() error {
 	return term.Flush()
 }
 
+func Size() (width int, height int) {
+	return term.Size()
+}
+
 func Print(args ...interface{}) (int, error) {
 	return fmt.Fprint(term, args...)
 }
@@ -68,6 +79,7 @@ func ReadPassowrd(prompt string) (line string, err error) {
 func New(input *os.File, output *os.File) (rw ReadWriter, err error) {
 	var reader Reader
 	var writer Writer
+	var profile = DetectColorProfile(output)
 
 	if terminal.IsTerminal(int(output.Fd())) {
 		term := terminal.NewTerminal(struct {
@@ -79,15 +91,15 @@ func New(input *os.File, output *os.File) (rw ReadWriter, err error) {
 			return
 		}
 
-		if writer, err = newWriter(term, output); err != nil {
+		if writer, err = newWriter(term, output, profile); err != nil {
 			return
 		}
 	} else {
 		reader = newFileReader(input)
-		writer = newFileWriter(output)
+		writer = newFileWriter(output, profile)
 	}
 
-	rw = readWriter{reader, writer}
+	rw = readWriter{reader, writer, !IsUTF8Locale()}
 	return
 }
 
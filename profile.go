@@ -0,0 +1,328 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// ColorProfile represents the color capabilities of a terminal.
+type ColorProfile int
+
+const (
+	// NoColor is the profile of outputs that don't support escape sequences,
+	// all styles are removed when writing to those.
+	NoColor ColorProfile = iota
+
+	// ANSIColor is the profile of terminals supporting the 16 basic colors.
+	ANSIColor
+
+	// ANSI256Color is the profile of terminals supporting the 256 colors
+	// palette.
+	ANSI256Color
+
+	// TrueColor is the profile of terminals supporting 24-bit colors.
+	TrueColor
+)
+
+func (p ColorProfile) String() string {
+	switch p {
+	case NoColor:
+		return "none"
+	case ANSIColor:
+		return "16"
+	case ANSI256Color:
+		return "256"
+	case TrueColor:
+		return "truecolor"
+	default:
+		return "ColorProfile(" + strconv.Itoa(int(p)) + ")"
+	}
+}
+
+// DetectColorProfile returns the color profile of f, based on whether it is
+// a terminal and on the NO_COLOR, FORCE_COLOR, CLICOLOR, CLICOLOR_FORCE,
+// COLORTERM and TERM environment variables.
+func DetectColorProfile(f *os.File) ColorProfile {
+	return detectColorProfile(os.Getenv, terminal.IsTerminal(int(f.Fd())))
+}
+
+func detectColorProfile(getenv func(string) string, isTerminal bool) ColorProfile {
+	profile := colorProfileOfTerm(getenv("TERM"), getenv("COLORTERM"))
+
+	if force := getenv("FORCE_COLOR"); len(force) != 0 {
+		switch strings.ToLower(force) {
+		case "0", "false":
+			return NoColor
+		case "1", "true":
+			return maxColorProfile(profile, ANSIColor)
+		case "2":
+			return ANSI256Color
+		case "3":
+			return TrueColor
+		}
+		return maxColorProfile(profile, ANSIColor)
+	}
+
+	if len(getenv("NO_COLOR")) != 0 {
+		return NoColor
+	}
+
+	if force := getenv("CLICOLOR_FORCE"); len(force) != 0 && force != "0" {
+		return maxColorProfile(profile, ANSIColor)
+	}
+
+	if !isTerminal || getenv("CLICOLOR") == "0" {
+		return NoColor
+	}
+
+	return profile
+}
+
+func colorProfileOfTerm(term string, colorterm string) ColorProfile {
+	switch strings.ToLower(colorterm) {
+	case "truecolor", "24bit":
+		return TrueColor
+	}
+
+	switch term = strings.ToLower(term); {
+	case term == "", term == "dumb":
+		return NoColor
+	case strings.Contains(term, "truecolor"),
+		strings.Contains(term, "24bit"),
+		strings.HasSuffix(term, "-direct"):
+		return TrueColor
+	case strings.Contains(term, "256color"):
+		return ANSI256Color
+	default:
+		return ANSIColor
+	}
+}
+
+func maxColorProfile(p1 ColorProfile, p2 ColorProfile) ColorProfile {
+	if p1 > p2 {
+		return p1
+	}
+	return p2
+}
+
+// ConvertStylesInString is like ConvertStyles but takes a string as argument.
+func ConvertStylesInString(s string, p ColorProfile) string {
+	return string(ConvertStyles([]byte(s), p))
+}
+
+// ConvertStyles rewrites the colors of style sequences found in b to the
+// closest colors supported by p.
+//
+// With the NoColor profile all escape sequences are removed, like StripStyles
+// does. The returned slice may share the backing array of b.
+func ConvertStyles(b []byte, p ColorProfile) []byte {
+	switch {
+	case p >= TrueColor:
+		return b
+	case p <= NoColor:
+		return StripStyles(b)
+	case bytes.IndexByte(b, esc) < 0:
+		return b
+	}
+
+	s := make([]byte, 0, len(b))
+
+	ForEachToken(b, func(t Token) {
+		if t.Kind == CSIToken && t.Final() == 'm' && len(t.Intermediates()) == 0 {
+			if codes, ok := parseStyleCodes(t.Params()); ok {
+				s = appendStyleCodes(s, convertStyleCodes(codes, p)...)
+				return
+			}
+		}
+		s = append(s, t.Bytes...)
+	})
+
+	return s
+}
+
+func parseStyleCodes(params []byte) (codes []int, ok bool) {
+	if len(params) == 0 {
+		return []int{0}, true
+	}
+
+	for _, p := range bytes.Split(params, []byte{';'}) {
+		c := 0
+
+		if len(p) != 0 {
+			v, err := strconv.ParseUint(string(p), 10, 16)
+			if err != nil {
+				return nil, false
+			}
+			c = int(v)
+		}
+
+		codes = append(codes, c)
+	}
+
+	return codes, true
+}
+
+func convertStyleCodes(codes []int, p ColorProfile) []int {
+	conv := make([]int, 0, len(codes))
+
+	for i := 0; i < len(codes); i++ {
+		switch c := codes[i]; c {
+		case extendedFG, extendedBG:
+			rest := codes[i+1:]
+
+			switch {
+			case len(rest) >= 2 && rest[0] == color256 && rest[1] <= 255:
+				conv = append(conv, convertColor256(c, rest[1], p)...)
+				i += 2
+			case len(rest) >= 4 && rest[0] == colorRGB && rest[1] <= 255 && rest[2] <= 255 && rest[3] <= 255:
+				conv = append(conv, convertColorRGB(c, rest[1], rest[2], rest[3], p)...)
+				i += 4
+			default:
+				// Malformed extended color, we can't know how many codes it
+				// was supposed to span so we drop the rest of the sequence.
+				return conv
+			}
+
+		default:
+			conv = append(conv, c)
+		}
+	}
+
+	return conv
+}
+
+func convertColor256(mode int, n int, p ColorProfile) []int {
+	if p >= ANSI256Color {
+		return []int{mode, color256, n}
+	}
+
+	if n < 16 {
+		return []int{ansiColorCode(mode, n)}
+	}
+
+	r, g, b := color256ToRGB(n)
+	return []int{ansiColorCode(mode, nearestANSIColor(r, g, b))}
+}
+
+func convertColorRGB(mode int, r, g, b int, p ColorProfile) []int {
+	if p >= ANSI256Color {
+		return []int{mode, color256, nearestColor256(r, g, b)}
+	}
+	return []int{ansiColorCode(mode, nearestANSIColor(r, g, b))}
+}
+
+func ansiColorCode(mode int, n int) int {
+	base := 30
+
+	if n >= 8 {
+		base, n = 90, n-8
+	}
+
+	if mode == extendedBG {
+		base += 10
+	}
+
+	return base + n
+}
+
+// Default RGB values of the 16 basic colors in xterm.
+var ansiColors = [16][3]int{
+	{0, 0, 0},
+	{205, 0, 0},
+	{0, 205, 0},
+	{205, 205, 0},
+	{0, 0, 238},
+	{205, 0, 205},
+	{0, 205, 205},
+	{229, 229, 229},
+	{127, 127, 127},
+	{255, 0, 0},
+	{0, 255, 0},
+	{255, 255, 0},
+	{92, 92, 255},
+	{255, 0, 255},
+	{0, 255, 255},
+	{255, 255, 255},
+}
+
+// Intensity levels of each component of the 6x6x6 color cube of the 256
+// colors palette.
+var colorCubeLevels = [6]int{0, 95, 135, 175, 215, 255}
+
+func color256ToRGB(n int) (r, g, b int) {
+	switch {
+	case n < 16:
+		c := ansiColors[n]
+		return c[0], c[1], c[2]
+	case n < 232:
+		n -= 16
+		return colorCubeLevels[n/36], colorCubeLevels[(n/6)%6], colorCubeLevels[n%6]
+	default:
+		v := 8 + 10*(n-232)
+		return v, v, v
+	}
+}
+
+func nearestANSIColor(r, g, b int) int {
+	best, dist := 0, -1
+
+	for i, c := range ansiColors {
+		if d := colorDistance(r, g, b, c[0], c[1], c[2]); dist < 0 || d < dist {
+			best, dist = i, d
+		}
+	}
+
+	return best
+}
+
+func nearestColor256(r, g, b int) int {
+	ri, gi, bi := nearestCubeLevel(r), nearestCubeLevel(g), nearestCubeLevel(b)
+	cube := 16 + 36*ri + 6*gi + bi
+	cubeDist := colorDistance(r, g, b, colorCubeLevels[ri], colorCubeLevels[gi], colorCubeLevels[bi])
+
+	gray := 232
+	grayDist := -1
+
+	for i := 0; i != 24; i++ {
+		v := 8 + 10*i
+
+		if d := colorDistance(r, g, b, v, v, v); grayDist < 0 || d < grayDist {
+			gray, grayDist = 232+i, d
+		}
+	}
+
+	if grayDist < cubeDist {
+		return gray
+	}
+
+	return cube
+}
+
+func nearestCubeLevel(v int) int {
+	best, dist := 0, -1
+
+	for i, l := range colorCubeLevels {
+		d := v - l
+
+		if d < 0 {
+			d = -d
+		}
+
+		if dist < 0 || d < dist {
+			best, dist = i, d
+		}
+	}
+
+	return best
+}
+
+func colorDistance(r1, g1, b1, r2, g2, b2 int) int {
+	// Weighted euclidean distance, approximating the sensitivity of the human
+	// eye to each component.
+	dr, dg, db := r1-r2, g1-g2, b1-b2
+	return 2*dr*dr + 4*dg*dg + 3*db*db
+}
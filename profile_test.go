@@ -0,0 +1,185 @@
+package cli
+
+import "testing"
+
+func TestDetectColorProfile(t *testing.T) {
+	tests := []struct {
+		env     map[string]string
+		term    bool
+		profile ColorProfile
+	}{
+		{
+			env:     map[string]string{},
+			term:    true,
+			profile: NoColor,
+		},
+		{
+			env:     map[string]string{"TERM": "xterm"},
+			term:    true,
+			profile: ANSIColor,
+		},
+		{
+			env:     map[string]string{"TERM": "xterm-256color"},
+			term:    true,
+			profile: ANSI256Color,
+		},
+		{
+			env:     map[string]string{"TERM": "xterm-256color", "COLORTERM": "truecolor"},
+			term:    true,
+			profile: TrueColor,
+		},
+		{
+			env:     map[string]string{"TERM": "xterm-direct"},
+			term:    true,
+			profile: TrueColor,
+		},
+		{
+			env:     map[string]string{"TERM": "dumb"},
+			term:    true,
+			profile: NoColor,
+		},
+		{
+			env:     map[string]string{"TERM": "xterm-256color"},
+			term:    false,
+			profile: NoColor,
+		},
+		{
+			env:     map[string]string{"TERM": "xterm-256color", "NO_COLOR": "1"},
+			term:    true,
+			profile: NoColor,
+		},
+		{
+			env:     map[string]string{"TERM": "xterm-256color", "CLICOLOR": "0"},
+			term:    true,
+			profile: NoColor,
+		},
+		{
+			env:     map[string]string{"CLICOLOR_FORCE": "1"},
+			term:    false,
+			profile: ANSIColor,
+		},
+		{
+			env:     map[string]string{"TERM": "xterm-256color", "FORCE_COLOR": "1"},
+			term:    false,
+			profile: ANSI256Color,
+		},
+		{
+			env:     map[string]string{"FORCE_COLOR": "3", "NO_COLOR": "1"},
+			term:    false,
+			profile: TrueColor,
+		},
+		{
+			env:     map[string]string{"TERM": "xterm-256color", "FORCE_COLOR": "0"},
+			term:    true,
+			profile: NoColor,
+		},
+	}
+
+	for _, test := range tests {
+		getenv := func(key string) string { return test.env[key] }
+
+		if profile := detectColorProfile(getenv, test.term); profile != test.profile {
+			t.Errorf("%v (terminal: %t): expected %s but found %s", test.env, test.term, test.profile, profile)
+		}
+	}
+}
+
+func TestConvertStyles(t *testing.T) {
+	tests := []struct {
+		in      string
+		profile ColorProfile
+		out     string
+	}{
+		{
+			in:      RGB(255, 0, 0).S("Hello"),
+			profile: TrueColor,
+			out:     "\033[38;2;255;0;0mHello\033[0m",
+		},
+		{
+			in:      RGB(255, 0, 0).S("Hello"),
+			profile: ANSI256Color,
+			out:     "\033[38;5;196mHello\033[0m",
+		},
+		{
+			in:      BgRGB(128, 128, 128).S("Hello"),
+			profile: ANSI256Color,
+			out:     "\033[48;5;244mHello\033[0m",
+		},
+		{
+			in:      Style(Bold, RGB(255, 0, 0)).S("Hello"),
+			profile: ANSIColor,
+			out:     "\033[1;91mHello\033[0m",
+		},
+		{
+			in:      BgColor256(4).S("Hello"),
+			profile: ANSIColor,
+			out:     "\033[44mHello\033[0m",
+		},
+		{
+			in:      Color256(46).S("Hello"),
+			profile: ANSIColor,
+			out:     "\033[92mHello\033[0m",
+		},
+		{
+			in:      "\033[2K" + Style(Bold, Color256(46)).S("Hello"),
+			profile: ANSIColor,
+			out:     "\033[2K\033[1;92mHello\033[0m",
+		},
+		{
+			in:      "\033[2K" + Style(Bold, RGB(255, 0, 0)).S("Hello"),
+			profile: NoColor,
+			out:     "Hello",
+		},
+	}
+
+	for _, test := range tests {
+		if s := ConvertStylesInString(test.in, test.profile); s != test.out {
+			t.Errorf("%q (%s): expected %q but found %q", test.in, test.profile, test.out, s)
+		}
+	}
+}
+
+func TestColorWriterSplitSequences(t *testing.T) {
+	inputs := []string{
+		RGB(255, 0, 0).S("Hello") + " " + BgRGB(0, 0, 255).S("World"),
+		"\033]8;;https://example.com\033\\link\033]8;;\033\\",
+		"\033]0;title\007" + Bold.S("!"),
+		"\033(B" + Color256(46).S("Hi"),
+	}
+
+	for _, in := range inputs {
+		for _, profile := range []ColorProfile{NoColor, ANSIColor, ANSI256Color} {
+			expected := ConvertStylesInString(in, profile)
+
+			// Each split point of the input is tested, sequences cut by a
+			// write must be converted as a whole.
+			for i := 0; i <= len(in); i++ {
+				b := &sizedWriter{}
+				w := NewColorWriter(b, profile)
+				w.Write([]byte(in[:i]))
+				w.Write([]byte(in[i:]))
+				w.Flush()
+
+				if s := b.String(); s != expected {
+					t.Errorf("%q (%s) split at %d: expected %q but found %q", in, profile, i, expected, s)
+				}
+			}
+		}
+	}
+}
+
+func TestColorWriterFlushPending(t *testing.T) {
+	b := &sizedWriter{}
+	w := NewColorWriter(b, ANSIColor)
+	w.Write([]byte("Hello\033[1;3"))
+
+	if s := b.String(); s != "Hello" {
+		t.Errorf("the incomplete sequence was written: %q", s)
+	}
+
+	w.Flush()
+
+	if s := b.String(); s != "Hello\033[1;3" {
+		t.Errorf("the incomplete sequence was not written on flush: %q", s)
+	}
+}
@@ -33,8 +33,10 @@ func (s StyleSet) Format(f fmt.State, _ rune) {
 	s.WriteTo(f)
 }
 
-func (s StyleSet) WriteTo(w io.Writer) (int, error) {
-	return w.Write(s.Bytes())
+// WriteTo writes the escape sequence of s to w, it implements io.WriterTo.
+func (s StyleSet) WriteTo(w io.Writer) (int64, error) {
+	n, err := w.Write(s.Bytes())
+	return int64(n), err
 }
 
 func Style(styles ...StyleSet) StyleSet {
@@ -65,7 +67,7 @@ var (
 	Magenta StyleSet = StyleSet{35}
 	Cyan    StyleSet = StyleSet{36}
 	White   StyleSet = StyleSet{37}
-	Default StyleSet = StyleSet{38}
+	Default StyleSet = StyleSet{39}
 
 	BlackBG   StyleSet = StyleSet{40}
 	RedBG     StyleSet = StyleSet{41}
@@ -75,7 +77,25 @@ var (
 	MagentaBG StyleSet = StyleSet{45}
 	CyanBG    StyleSet = StyleSet{46}
 	WhiteBG   StyleSet = StyleSet{47}
-	DefaultBG StyleSet = StyleSet{48}
+	DefaultBG StyleSet = StyleSet{49}
+
+	BrightBlack   StyleSet = StyleSet{90}
+	BrightRed     StyleSet = StyleSet{91}
+	BrightGreen   StyleSet = StyleSet{92}
+	BrightYellow  StyleSet = StyleSet{93}
+	BrightBlue    StyleSet = StyleSet{94}
+	BrightMagenta StyleSet = StyleSet{95}
+	BrightCyan    StyleSet = StyleSet{96}
+	BrightWhite   StyleSet = StyleSet{97}
+
+	BrightBlackBG   StyleSet = StyleSet{100}
+	BrightRedBG     StyleSet = StyleSet{101}
+	BrightGreenBG   StyleSet = StyleSet{102}
+	BrightYellowBG  StyleSet = StyleSet{103}
+	BrightBlueBG    StyleSet = StyleSet{104}
+	BrightMagentaBG StyleSet = StyleSet{105}
+	BrightCyanBG    StyleSet = StyleSet{106}
+	BrightWhiteBG   StyleSet = StyleSet{107}
 )
 
 func ForEachByteInString(s string, do func(byte)) {
@@ -83,21 +103,12 @@ func ForEachByteInString(s string, do func(byte)) {
 }
 
 func ForEachByte(b []byte, do func(byte)) {
-	for i, n := 0, len(b); i != n; {
-		c := b[i]
-
-		if c == '\033' {
-			for i = i + 1; i != n; i++ {
-				if b[i] == 'm' {
-					i++
-					break
-				}
+	for t := NewTokenizer(b); t.Next(); {
+		if tok := t.Token(); tok.Kind == TextToken {
+			for _, c := range tok.Bytes {
+				do(c)
 			}
-			continue
 		}
-
-		do(c)
-		i++
 	}
 }
 
@@ -106,21 +117,14 @@ func ForEachRuneInString(s string, do func(rune)) {
 }
 
 func ForEachRune(b []byte, do func(rune)) {
-	for i, n := 0, len(b); i != n; {
-		c, z := utf8.DecodeRune(b[i:])
-
-		if c == '\033' {
-			for i = i + 1; i != n; i++ {
-				if b[i] == 'm' {
-					i++
-					break
-				}
+	for t := NewTokenizer(b); t.Next(); {
+		if tok := t.Token(); tok.Kind == TextToken {
+			for s := tok.Bytes; len(s) != 0; {
+				c, z := utf8.DecodeRune(s)
+				do(c)
+				s = s[z:]
 			}
-			continue
 		}
-
-		do(c)
-		i += z
 	}
 }
 
@@ -133,15 +137,33 @@ func RuneCount(b []byte) (n int) {
 	return
 }
 
+// DisplayWidthInString is like DisplayWidth but takes a string as argument.
+func DisplayWidthInString(s string) int {
+	return DisplayWidth([]byte(s))
+}
+
+// DisplayWidth returns the number of terminal columns needed to display b,
+// ignoring style sequences.
+//
+// Unlike RuneCount, the width accounts for wide characters, combining marks,
+// variation selectors and grapheme clusters like emoji joined with zero-width
+// joiners or flags made of regional indicators.
+func DisplayWidth(b []byte) int {
+	d := displayWidth{}
+	ForEachRune(b, d.add)
+	return d.n
+}
+
 func StripStylesInString(s string) string {
 	return string(StripStyles([]byte(s)))
 }
 
 func StripStyles(b []byte) []byte {
 	j := 0
-	ForEachByte(b, func(c byte) {
-		b[j] = c
-		j++
+	ForEachToken(b, func(t Token) {
+		if t.Kind == TextToken {
+			j += copy(b[j:], t.Bytes)
+		}
 	})
 	return b[:j]
 }
@@ -83,6 +83,22 @@ func TestStripStyles(t *testing.T) {
 			in:  "Hello \033[32mWorld!\033[0m",
 			out: "Hello World!",
 		},
+		{
+			in:  "\033[2K\033[1AHello World!",
+			out: "Hello World!",
+		},
+		{
+			in:  "Hello \033]8;;https://example.com\007World!\033]8;;\033\\",
+			out: "Hello World!",
+		},
+		{
+			in:  "Hello\033\nWorld!",
+			out: "Hello\nWorld!",
+		},
+		{
+			in:  "Hello World!\033",
+			out: "Hello World!",
+		},
 	}
 
 	for _, test := range tests {
@@ -5,6 +5,8 @@ import "fmt"
 type Table struct {
 	cols []string
 	rows [][]string
+	foot [][]string
+	span map[[2]int]int
 }
 
 func NewTable(columns ...string) *Table {
@@ -35,6 +37,28 @@ func NewTableFromView(view TableView) *Table {
 		t.rows[j] = row
 	}
 
+	if s, ok := view.(SpanTableView); ok {
+		for j := 0; j != rows; j++ {
+			for i := 0; i != cols; i++ {
+				if n := s.CellSpan(i, j); n > 1 {
+					t.SetCellSpan(i, j, n)
+				}
+			}
+		}
+	}
+
+	if f, ok := view.(FooterTableView); ok {
+		for j, n := 0, f.FooterSize(); j != n; j++ {
+			row := make([]string, cols)
+
+			for i := 0; i != cols; i++ {
+				row[i] = f.FooterCell(i, j)
+			}
+
+			t.foot = append(t.foot, row)
+		}
+	}
+
 	return t
 }
 
@@ -47,6 +71,43 @@ func (t *Table) Append(row ...string) *Table {
 	return t
 }
 
+// AppendFooter adds a footer row to the table, footer rows are rendered after
+// the other rows.
+func (t *Table) AppendFooter(row ...string) *Table {
+	if len(t.cols) != len(row) {
+		panic(fmt.Sprintf("cli.(*Table).AppendFooter: invalid row length (expected %d but found %d)", len(t.cols), len(row)))
+	}
+
+	t.foot = append(t.foot, row)
+	return t
+}
+
+func (t *Table) FooterCell(col int, row int) string {
+	return t.foot[row][col]
+}
+
+func (t *Table) FooterSize() int {
+	return len(t.foot)
+}
+
+// SetCellSpan makes the cell at col and row span n columns, the cells that it
+// covers are not rendered.
+func (t *Table) SetCellSpan(col int, row int, n int) *Table {
+	if t.span == nil {
+		t.span = make(map[[2]int]int)
+	}
+
+	t.span[[2]int{col, row}] = n
+	return t
+}
+
+func (t *Table) CellSpan(col int, row int) int {
+	if n, ok := t.span[[2]int{col, row}]; ok {
+		return n
+	}
+	return 1
+}
+
 func (t *Table) Column(col int) string {
 	return t.cols[col]
 }
@@ -0,0 +1,339 @@
+package cli
+
+import (
+	"bytes"
+	"io"
+	"strings"
+)
+
+// TableBorder describes the characters used to draw the borders of tables.
+type TableBorder struct {
+	// Characters drawing the vertical lines of the table, on the left of the
+	// first column, between columns, and on the right of the last column.
+	Left      string
+	Separator string
+	Right     string
+
+	// Horizontal lines drawn above the table, under the header, above the
+	// footer and below the table. Lines with no fill character are not drawn.
+	Top    TableBorderLine
+	Header TableBorderLine
+	Footer TableBorderLine
+	Bottom TableBorderLine
+
+	// Padding is the number of spaces added on each side of the cells.
+	Padding int
+
+	// Style is applied to all border characters.
+	Style StyleSet
+}
+
+// TableBorderLine describes the characters used to draw a horizontal line of
+// a table border.
+type TableBorderLine struct {
+	Left      string
+	Fill      string
+	Separator string
+	Right     string
+
+	// Aligned lines mark the alignment of columns with ':' characters, like
+	// the header line of Markdown tables.
+	Aligned bool
+}
+
+var (
+	NoBorder = TableBorder{
+		Separator: " ",
+		Footer:    TableBorderLine{"", "─", " ", "", false},
+	}
+
+	// asciiNoBorder replaces NoBorder when writing to a Writer which doesn't
+	// support UTF-8.
+	asciiNoBorder = TableBorder{
+		Separator: " ",
+		Footer:    TableBorderLine{"", "-", " ", "", false},
+	}
+
+	ASCIIBorder = TableBorder{
+		Left:      "|",
+		Separator: "|",
+		Right:     "|",
+		Top:       TableBorderLine{"+", "-", "+", "+", false},
+		Header:    TableBorderLine{"+", "-", "+", "+", false},
+		Footer:    TableBorderLine{"+", "-", "+", "+", false},
+		Bottom:    TableBorderLine{"+", "-", "+", "+", false},
+		Padding:   1,
+	}
+
+	LightBorder = TableBorder{
+		Left:      "│",
+		Separator: "│",
+		Right:     "│",
+		Top:       TableBorderLine{"┌", "─", "┬", "┐", false},
+		Header:    TableBorderLine{"├", "─", "┼", "┤", false},
+		Footer:    TableBorderLine{"├", "─", "┼", "┤", false},
+		Bottom:    TableBorderLine{"└", "─", "┴", "┘", false},
+		Padding:   1,
+	}
+
+	HeavyBorder = TableBorder{
+		Left:      "┃",
+		Separator: "┃",
+		Right:     "┃",
+		Top:       TableBorderLine{"┏", "━", "┳", "┓", false},
+		Header:    TableBorderLine{"┣", "━", "╋", "┫", false},
+		Footer:    TableBorderLine{"┣", "━", "╋", "┫", false},
+		Bottom:    TableBorderLine{"┗", "━", "┻", "┛", false},
+		Padding:   1,
+	}
+
+	DoubleBorder = TableBorder{
+		Left:      "║",
+		Separator: "║",
+		Right:     "║",
+		Top:       TableBorderLine{"╔", "═", "╦", "╗", false},
+		Header:    TableBorderLine{"╠", "═", "╬", "╣", false},
+		Footer:    TableBorderLine{"╠", "═", "╬", "╣", false},
+		Bottom:    TableBorderLine{"╚", "═", "╩", "╝", false},
+		Padding:   1,
+	}
+
+	RoundedBorder = TableBorder{
+		Left:      "│",
+		Separator: "│",
+		Right:     "│",
+		Top:       TableBorderLine{"╭", "─", "┬", "╮", false},
+		Header:    TableBorderLine{"├", "─", "┼", "┤", false},
+		Footer:    TableBorderLine{"├", "─", "┼", "┤", false},
+		Bottom:    TableBorderLine{"╰", "─", "┴", "╯", false},
+		Padding:   1,
+	}
+
+	MarkdownBorder = TableBorder{
+		Left:      "|",
+		Separator: "|",
+		Right:     "|",
+		Header:    TableBorderLine{"|", "-", "|", "|", true},
+		Footer:    TableBorderLine{"|", "-", "|", "|", false},
+		Padding:   1,
+	}
+)
+
+// tableBorder returns the border of tables rendered to w, which is NoBorder when
+// none was set, with an ASCII footer line if w doesn't support UTF-8.
+func tableBorder(w io.Writer, border *TableBorder) *TableBorder {
+	if border != nil {
+		return border
+	}
+
+	if a, ok := w.(asciiWriter); ok && a.asciiOnly() {
+		return &asciiNoBorder
+	}
+
+	return &NoBorder
+}
+
+// aligned returns true if one of the lines of b marks the alignment of columns,
+// which means the table is rendered in Markdown.
+func (b *TableBorder) aligned() bool {
+	return b.Top.Aligned || b.Header.Aligned || b.Footer.Aligned || b.Bottom.Aligned
+}
+
+// escapedTableView returns t with the '|' characters of its column names and
+// cells escaped when the border is aligned, otherwise they would be read as
+// column separators of the Markdown table.
+func escapedTableView(t TableView, border *TableBorder) TableView {
+	if border.aligned() {
+		t = pipeEscapedTableView{t}
+	}
+	return t
+}
+
+type pipeEscapedTableView struct {
+	TableView
+}
+
+func (t pipeEscapedTableView) Column(col int) string {
+	return escapePipes(t.TableView.Column(col))
+}
+
+func (t pipeEscapedTableView) Cell(col int, row int) string {
+	return escapePipes(t.TableView.Cell(col, row))
+}
+
+func (t pipeEscapedTableView) CellSpan(col int, row int) int {
+	return tableCellSpan(t.TableView, col, row)
+}
+
+func (t pipeEscapedTableView) FooterCell(col int, row int) string {
+	return escapePipes(tableFooterCell(t.TableView, col, row))
+}
+
+func (t pipeEscapedTableView) FooterSize() int {
+	return tableFooterSize(t.TableView)
+}
+
+func escapePipes(s string) string {
+	return strings.Replace(s, "|", `\|`, -1)
+}
+
+// width returns the number of columns occupied by the border of a table with
+// n columns.
+func (b *TableBorder) width(n int) int {
+	if n == 0 {
+		return 0
+	}
+	return DisplayWidthInString(b.Left) +
+		DisplayWidthInString(b.Right) +
+		DisplayWidthInString(b.Separator)*(n-1) +
+		b.Padding*2*n
+}
+
+func (b *TableBorder) glyph(s string) string {
+	if len(s) == 0 || len(b.Style) == 0 {
+		return s
+	}
+	return b.Style.S(s)
+}
+
+func (b *TableBorder) renderLine(w io.Writer, line TableBorderLine, layout *tableLayout) error {
+	return b.renderSpannedLine(w, line, layout, nil)
+}
+
+// renderSpannedLine is like renderLine but does not draw separators between
+// the columns merged by span, which may be nil.
+func (b *TableBorder) renderSpannedLine(w io.Writer, line TableBorderLine, layout *tableLayout, span func(int) int) (err error) {
+	if len(line.Fill) == 0 {
+		return
+	}
+
+	buf := &bytes.Buffer{}
+	buf.WriteString(line.Left)
+
+	for n := 0; n < len(layout.columns); {
+		i := layout.columns[n]
+
+		if n != 0 {
+			buf.WriteString(line.Separator)
+		}
+
+		width, next := b.spanWidth(layout, n, spanOf(span, i))
+		fill := strings.Repeat(line.Fill, width+2*b.Padding)
+
+		if line.Aligned && len(fill) != 0 {
+			fill = alignFill(fill, line.Fill, layout.aligns[i])
+		}
+
+		buf.WriteString(fill)
+		n = next
+	}
+
+	buf.WriteString(line.Right)
+
+	if _, err = io.WriteString(w, b.glyph(buf.String())); err == nil {
+		_, err = io.WriteString(w, "\n")
+	}
+
+	return
+}
+
+// spanWidth returns the width of a cell spanning span columns, starting with
+// the visible column at position n of the layout, and the position of the next
+// visible column after the cell. Hidden columns are skipped, the separators and
+// padding between merged columns become part of the cell.
+func (b *TableBorder) spanWidth(layout *tableLayout, n int, span int) (width int, next int) {
+	i := layout.columns[n]
+	width = layout.widths[i]
+
+	for next = n + 1; next < len(layout.columns) && layout.columns[next] < i+span; next++ {
+		width += DisplayWidthInString(b.Separator) + 2*b.Padding + layout.widths[layout.columns[next]]
+	}
+
+	return
+}
+
+func spanOf(span func(int) int, col int) int {
+	if span == nil {
+		return 1
+	}
+	return span(col)
+}
+
+func alignFill(fill string, c string, align CellAlign) string {
+	n := len(fill) / len(c)
+
+	switch align {
+	case LeftAlign:
+		return ":" + strings.Repeat(c, n-1)
+	case RightAlign:
+		return strings.Repeat(c, n-1) + ":"
+	default:
+		if n < 2 {
+			return ":"
+		}
+		return ":" + strings.Repeat(c, n-2) + ":"
+	}
+}
+
+// tableRow describes a row of cells rendered by TableBorder.renderRow.
+type tableRow struct {
+	style StyleSet
+
+	// cell returns the content of the cell at col, truncated to width.
+	cell func(col int, width int) string
+
+	// span returns the number of columns covered by the cell at col, nil
+	// means that cells cover a single column.
+	span func(col int) int
+
+	// align returns the alignment of the cell at col, nil means that cells
+	// are aligned like their column.
+	align func(col int) CellAlign
+}
+
+func (b *TableBorder) renderRow(w io.Writer, layout *tableLayout, row tableRow) (err error) {
+	buf := &bytes.Buffer{}
+	tmp := &bytes.Buffer{}
+	pad := makeSpaces(b.Padding)
+	buf.WriteString(b.glyph(b.Left))
+
+	for n := 0; n < len(layout.columns); {
+		i := layout.columns[n]
+
+		if n != 0 {
+			if len(row.style) != 0 && len(strings.TrimSpace(b.Separator)) == 0 {
+				// Blank separators are part of the row, they get the row style
+				// so the row doesn't appear to have gaps.
+				buf.Write(restyle([]byte(b.Separator), row.style))
+			} else {
+				buf.WriteString(b.glyph(b.Separator))
+			}
+		}
+
+		width, next := b.spanWidth(layout, n, spanOf(row.span, i))
+		align := layout.aligns[i]
+
+		if row.align != nil {
+			align = row.align(i)
+		}
+
+		tmp.Reset()
+		tmp.Write(pad)
+		RenderCell(tmp, row.cell(i, width), width, align)
+		tmp.Write(pad)
+
+		if len(row.style) != 0 {
+			buf.Write(restyle(tmp.Bytes(), row.style))
+		} else {
+			buf.Write(tmp.Bytes())
+		}
+
+		n = next
+	}
+
+	buf.WriteString(b.glyph(b.Right))
+	buf.WriteByte('\n')
+
+	_, err = w.Write(buf.Bytes())
+	return
+}
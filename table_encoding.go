@@ -0,0 +1,307 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// TableFormats lists the formats supported by EncodeTableView.
+var TableFormats = []string{"table", "csv", "tsv", "json", "ndjson", "yaml"}
+
+// EncodeTableView writes t to w in the given format, which must be one of the
+// values of TableFormats. This makes it easy for programs to support a flag
+// selecting their output format.
+func EncodeTableView(w io.Writer, t TableView, format string) error {
+	switch strings.ToLower(format) {
+	case "", "table":
+		return RenderTableView(w, t)
+	case "csv":
+		return EncodeTableViewCSV(w, t)
+	case "tsv":
+		return EncodeTableViewTSV(w, t)
+	case "json":
+		return EncodeTableViewJSON(w, t)
+	case "ndjson":
+		return EncodeTableViewNDJSON(w, t)
+	case "yaml", "yml":
+		return EncodeTableViewYAML(w, t)
+	default:
+		return fmt.Errorf("cli.EncodeTableView: unsupported format %q (expected one of %s)", format, strings.Join(TableFormats, ", "))
+	}
+}
+
+// EncodeTableViewCSV writes t to w as comma-separated values, starting with a
+// header record holding the column names.
+func EncodeTableViewCSV(w io.Writer, t TableView) error {
+	cols, rows := t.Size()
+	enc := csv.NewWriter(w)
+
+	if err := enc.Write(tableColumnNames(t, cols)); err != nil {
+		return err
+	}
+
+	record := make([]string, cols)
+
+	for j := 0; j != rows; j++ {
+		for i := 0; i != cols; i++ {
+			record[i] = StripStylesInString(t.Cell(i, j))
+		}
+
+		if err := enc.Write(record); err != nil {
+			return err
+		}
+	}
+
+	enc.Flush()
+	return enc.Error()
+}
+
+// EncodeTableViewTSV writes t to w as tab-separated values, starting with a
+// header record holding the column names. Fields are never quoted, tabs, line
+// breaks and backslashes are escaped as \t, \n, \r and \\.
+func EncodeTableViewTSV(w io.Writer, t TableView) error {
+	cols, rows := t.Size()
+	b := bufio.NewWriter(w)
+
+	writeRecord := func(record []string) {
+		for i, field := range record {
+			if i != 0 {
+				b.WriteByte('\t')
+			}
+			tsvEscaper.WriteString(b, field)
+		}
+		b.WriteByte('\n')
+	}
+
+	writeRecord(tableColumnNames(t, cols))
+	record := make([]string, cols)
+
+	for j := 0; j != rows; j++ {
+		for i := 0; i != cols; i++ {
+			record[i] = StripStylesInString(t.Cell(i, j))
+		}
+		writeRecord(record)
+	}
+
+	return b.Flush()
+}
+
+var tsvEscaper = strings.NewReplacer(
+	"\\", "\\\\",
+	"\t", "\\t",
+	"\n", "\\n",
+	"\r", "\\r",
+)
+
+// EncodeTableViewJSON writes t to w as a JSON array of objects, one for each
+// row, with the column names as keys. Cells of views created by NewTableView
+// from numbers, booleans or nil pointers are encoded as typed values, other
+// cells as strings.
+func EncodeTableViewJSON(w io.Writer, t TableView) error {
+	cols, rows := t.Size()
+	names := tableColumnNames(t, cols)
+	b := bufio.NewWriter(w)
+
+	if rows == 0 {
+		b.WriteString("[]\n")
+		return b.Flush()
+	}
+
+	b.WriteString("[\n")
+
+	for j := 0; j != rows; j++ {
+		b.WriteString("  ")
+		b.Write(appendTableRowJSON(nil, t, names, j))
+
+		if (j + 1) != rows {
+			b.WriteByte(',')
+		}
+
+		b.WriteByte('\n')
+	}
+
+	b.WriteString("]\n")
+	return b.Flush()
+}
+
+// EncodeTableViewNDJSON writes t to w as newline-delimited JSON, each row
+// being encoded as an object with the column names as keys, like the objects
+// of EncodeTableViewJSON.
+func EncodeTableViewNDJSON(w io.Writer, t TableView) error {
+	cols, rows := t.Size()
+	names := tableColumnNames(t, cols)
+	b := bufio.NewWriter(w)
+
+	for j := 0; j != rows; j++ {
+		b.Write(appendTableRowJSON(nil, t, names, j))
+		b.WriteByte('\n')
+	}
+
+	return b.Flush()
+}
+
+func appendTableRowJSON(b []byte, t TableView, names []string, row int) []byte {
+	b = append(b, '{')
+
+	for i, name := range names {
+		if i != 0 {
+			b = append(b, ',')
+		}
+		b = appendJSONString(b, name)
+		b = append(b, ':')
+
+		if v, ok := tableCellScalar(t, i, row); ok {
+			b = append(b, v...)
+		} else {
+			b = appendJSONString(b, StripStylesInString(t.Cell(i, row)))
+		}
+	}
+
+	return append(b, '}')
+}
+
+func appendJSONString(b []byte, s string) []byte {
+	buf := bytes.NewBuffer(b)
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	b = buf.Bytes()
+	return b[:len(b)-1] // strip the trailing newline
+}
+
+// EncodeTableViewYAML writes t to w as a YAML sequence of mappings, one for
+// each row, with the column names as keys. Values are typed like the ones of
+// EncodeTableViewJSON.
+func EncodeTableViewYAML(w io.Writer, t TableView) error {
+	cols, rows := t.Size()
+	names := tableColumnNames(t, cols)
+	b := bufio.NewWriter(w)
+
+	if rows == 0 || cols == 0 {
+		b.WriteString("[]\n")
+		return b.Flush()
+	}
+
+	for j := 0; j != rows; j++ {
+		for i, name := range names {
+			if i == 0 {
+				b.WriteString("- ")
+			} else {
+				b.WriteString("  ")
+			}
+			b.WriteString(yamlString(name))
+			b.WriteString(": ")
+
+			if v, ok := tableCellScalar(t, i, j); ok {
+				b.WriteString(v)
+			} else {
+				b.WriteString(yamlString(StripStylesInString(t.Cell(i, j))))
+			}
+			b.WriteByte('\n')
+		}
+	}
+
+	return b.Flush()
+}
+
+// tableCellScalar returns the value of a cell as a JSON number, boolean or null
+// when the table view was created from Go values of these types, so encoders
+// can emit typed values. The representation is also valid YAML.
+//
+// Types with methods, like time.Duration, are encoded as their cells since the
+// methods likely define how they are formatted.
+func tableCellScalar(t TableView, col int, row int) (string, bool) {
+	v, ok := tableCellValue(t, col, row)
+	if !ok {
+		return "", false
+	}
+
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return "null", true
+		}
+		v = v.Elem()
+	}
+
+	if !v.CanInterface() || v.Type().NumMethod() != 0 {
+		return "", false
+	}
+
+	switch v.Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		// Marshaling fails on infinities and NaN, which are encoded as
+		// strings.
+		if b, err := json.Marshal(v.Interface()); err == nil {
+			return string(b), true
+		}
+	}
+
+	return "", false
+}
+
+// yamlString returns s as a YAML scalar, quoting it if it would otherwise be
+// interpreted as something else than a string.
+func yamlString(s string) string {
+	if yamlNeedsQuotes(s) {
+		return string(appendJSONString(nil, s))
+	}
+	return s
+}
+
+func yamlNeedsQuotes(s string) bool {
+	if len(s) == 0 || s[0] == ' ' || s[len(s)-1] == ' ' {
+		return true
+	}
+
+	switch strings.ToLower(s) {
+	case "~", "null", "true", "false", "yes", "no", "on", "off", "y", "n", ".inf", "-.inf", "+.inf", ".nan":
+		return true
+	}
+
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return true
+	}
+
+	if _, err := strconv.ParseInt(s, 0, 64); err == nil {
+		return true
+	}
+
+	if strings.ContainsRune("-?:,[]{}#&*!|>'\"%@`", rune(s[0])) {
+		return true
+	}
+
+	if strings.Contains(s, ": ") || strings.Contains(s, " #") || strings.HasSuffix(s, ":") {
+		return true
+	}
+
+	for _, c := range s {
+		if c < 0x20 || c == 0x7F {
+			return true
+		}
+	}
+
+	return false
+}
+
+func tableColumnNames(t TableView, cols int) []string {
+	names := make([]string, cols)
+
+	for i := range names {
+		// Grouped columns are named after their groups so names stay unique,
+		// for example "[LATENCY]P50" becomes "LATENCY.P50".
+		c := column(StripStylesInString(t.Column(i)))
+		names[i] = strings.Join(append(c.groups(), c.name()), ".")
+	}
+
+	return names
+}
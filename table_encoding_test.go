@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestEncodeTableView(t *testing.T) {
+	type T struct {
+		Name  string `table:"NAME:"`
+		Size  int    `table:":SIZE"`
+		Notes string `table:"NOTES"`
+		Ready bool   `table:"READY"`
+		Score *int   `table:":SCORE,default=-"`
+	}
+
+	score := 7
+	table := StyledTableView(NewTableView([]T{
+		{Name: "hello", Size: 42, Notes: "a, b", Ready: true, Score: &score},
+		{Name: Bold.S("world"), Size: 1024, Notes: `say "hi": <ok>`},
+	}), Bold)
+
+	tests := []struct {
+		format string
+		output string
+	}{
+		{
+			format: "csv",
+			output: "" +
+				"NAME,SIZE,NOTES,READY,SCORE\n" +
+				"hello,42,\"a, b\",true,7\n" +
+				"world,1024,\"say \"\"hi\"\": <ok>\",false,-\n",
+		},
+		{
+			format: "tsv",
+			output: "" +
+				"NAME\tSIZE\tNOTES\tREADY\tSCORE\n" +
+				"hello\t42\ta, b\ttrue\t7\n" +
+				"world\t1024\tsay \"hi\": <ok>\tfalse\t-\n",
+		},
+		{
+			format: "json",
+			output: "" +
+				"[\n" +
+				`  {"NAME":"hello","SIZE":42,"NOTES":"a, b","READY":true,"SCORE":7},` + "\n" +
+				`  {"NAME":"world","SIZE":1024,"NOTES":"say \"hi\": <ok>","READY":false,"SCORE":null}` + "\n" +
+				"]\n",
+		},
+		{
+			format: "ndjson",
+			output: "" +
+				`{"NAME":"hello","SIZE":42,"NOTES":"a, b","READY":true,"SCORE":7}` + "\n" +
+				`{"NAME":"world","SIZE":1024,"NOTES":"say \"hi\": <ok>","READY":false,"SCORE":null}` + "\n",
+		},
+		{
+			format: "yaml",
+			output: "" +
+				"- NAME: hello\n" +
+				"  SIZE: 42\n" +
+				"  NOTES: a, b\n" +
+				"  READY: true\n" +
+				"  SCORE: 7\n" +
+				"- NAME: world\n" +
+				"  SIZE: 1024\n" +
+				"  NOTES: \"say \\\"hi\\\": <ok>\"\n" +
+				"  READY: false\n" +
+				"  SCORE: null\n",
+		},
+	}
+
+	buffer := &bytes.Buffer{}
+
+	for _, test := range tests {
+		buffer.Reset()
+
+		if err := EncodeTableView(buffer, table, test.format); err != nil {
+			t.Errorf("%s: %s", test.format, err)
+		} else if s := buffer.String(); s != test.output {
+			t.Errorf("%s:\n- expected: %q\n- found:    %q", test.format, test.output, s)
+		}
+	}
+
+	if err := EncodeTableView(buffer, table, "xml"); err == nil {
+		t.Error("expected an error for an unsupported format")
+	}
+}
+
+func TestEncodeTableViewTSVEscape(t *testing.T) {
+	table := NewTable("A", "B").Append("tab\there", "line\nbreak").Append(`back\slash`, `"quoted"`)
+	buffer := &bytes.Buffer{}
+
+	const output = "" +
+		"A\tB\n" +
+		"tab\\there\tline\\nbreak\n" +
+		"back\\\\slash\t\"quoted\"\n"
+
+	if err := EncodeTableViewTSV(buffer, table); err != nil {
+		t.Error(err)
+	} else if s := buffer.String(); s != output {
+		t.Errorf("expected %q but found %q", output, s)
+	}
+}
+
+func TestEncodeEmptyTableView(t *testing.T) {
+	tests := []struct {
+		format string
+		output string
+	}{
+		{"csv", "A,B\n"},
+		{"tsv", "A\tB\n"},
+		{"json", "[]\n"},
+		{"ndjson", ""},
+		{"yaml", "[]\n"},
+	}
+
+	buffer := &bytes.Buffer{}
+
+	for _, test := range tests {
+		buffer.Reset()
+
+		if err := EncodeTableView(buffer, NewTable("A", "B"), test.format); err != nil {
+			t.Errorf("%s: %s", test.format, err)
+		} else if s := buffer.String(); s != test.output {
+			t.Errorf("%s: expected %q but found %q", test.format, test.output, s)
+		}
+	}
+}
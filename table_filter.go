@@ -0,0 +1,235 @@
+package cli
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// FilteredTableView returns a view of t which only has the rows for which
+// keep returns true. The row index passed to keep is the position of the row
+// in t.
+//
+// Footers made of aggregates, like the ones of SummarizedTableView, are
+// computed again from the remaining rows. Other footers are dropped since they
+// would not match the rows anymore.
+func FilteredTableView(t TableView, keep func(row int) bool) TableView {
+	_, rows := t.Size()
+	view := filteredTableView{
+		TableView: t,
+		rows:      make([]int, 0, rows),
+		footer:    &footerCache{},
+	}
+
+	for j := 0; j != rows; j++ {
+		if keep(j) {
+			view.rows = append(view.rows, j)
+		}
+	}
+
+	return view
+}
+
+type filteredTableView struct {
+	TableView
+	rows   []int
+	footer *footerCache
+}
+
+func (t filteredTableView) Cell(col int, row int) string {
+	return t.TableView.Cell(col, t.rows[row])
+}
+
+func (t filteredTableView) Size() (cols int, rows int) {
+	cols, _ = t.TableView.Size()
+	rows = len(t.rows)
+	return
+}
+
+func (t filteredTableView) value(col int, row int) (reflect.Value, bool) {
+	return tableCellValue(t.TableView, col, t.rows[row])
+}
+
+func (t filteredTableView) CellSpan(col int, row int) int {
+	return tableCellSpan(t.TableView, col, t.rows[row])
+}
+
+func (t filteredTableView) FooterCell(col int, row int) string {
+	agg, ok := tableAggregate(t.TableView, col)
+
+	switch {
+	case !ok:
+		return ""
+	case agg != 0:
+		return t.footer.get(col, func() string { return aggregateColumn(t, col, agg) })
+	default:
+		return tableFooterCell(t.TableView, col, row)
+	}
+}
+
+func (t filteredTableView) FooterSize() int {
+	if cols, _ := t.Size(); cols != 0 {
+		if _, ok := tableAggregate(t.TableView, 0); ok {
+			return tableFooterSize(t.TableView)
+		}
+	}
+	return 0
+}
+
+func (t filteredTableView) aggregate(col int) (Aggregate, bool) {
+	return tableAggregate(t.TableView, col)
+}
+
+func (t filteredTableView) format(col int, v reflect.Value) string {
+	return formatTableCell(t.TableView, col, v)
+}
+
+// ProjectedTableView returns a view of t which only has the columns with the
+// given names, in the order they were passed. Names are matched without the
+// alignment markers of the column specs, ignoring case.
+func ProjectedTableView(t TableView, names ...string) (TableView, error) {
+	view := projectedTableView{
+		TableView: t,
+		cols:      make([]int, len(names)),
+	}
+
+	for i, name := range names {
+		if view.cols[i] = findColumn(t, name); view.cols[i] < 0 {
+			return nil, fmt.Errorf("cli.ProjectedTableView: no column named %q", name)
+		}
+	}
+
+	return view, nil
+}
+
+type projectedTableView struct {
+	TableView
+	cols []int
+}
+
+func (t projectedTableView) Column(col int) string {
+	return t.TableView.Column(t.cols[col])
+}
+
+func (t projectedTableView) Cell(col int, row int) string {
+	return t.TableView.Cell(t.cols[col], row)
+}
+
+func (t projectedTableView) Size() (cols int, rows int) {
+	_, rows = t.TableView.Size()
+	cols = len(t.cols)
+	return
+}
+
+// CellSpan forwards the spans of t as long as the columns they cover are
+// projected next to each other and in the same order, a span is cut at the
+// first column which is not.
+func (t projectedTableView) CellSpan(col int, row int) int {
+	n := tableCellSpan(t.TableView, t.cols[col], row)
+	span := 1
+
+	for span < n && col+span < len(t.cols) && t.cols[col+span] == t.cols[col]+span {
+		span++
+	}
+
+	return span
+}
+
+func (t projectedTableView) value(col int, row int) (reflect.Value, bool) {
+	return tableCellValue(t.TableView, t.cols[col], row)
+}
+
+func (t projectedTableView) FooterCell(col int, row int) string {
+	return tableFooterCell(t.TableView, t.cols[col], row)
+}
+
+func (t projectedTableView) FooterSize() int {
+	return tableFooterSize(t.TableView)
+}
+
+func (t projectedTableView) aggregate(col int) (Aggregate, bool) {
+	return tableAggregate(t.TableView, t.cols[col])
+}
+
+func (t projectedTableView) format(col int, v reflect.Value) string {
+	return formatTableCell(t.TableView, t.cols[col], v)
+}
+
+// RenamedTableView returns a view of t where columns are renamed according to
+// the names map, which associates current column names with new ones.
+//
+// Columns keep their alignment unless the new name has alignment markers.
+func RenamedTableView(t TableView, names map[string]string) (TableView, error) {
+	cols, _ := t.Size()
+	view := renamedTableView{
+		TableView: t,
+		names:     make([]string, cols),
+	}
+
+	for i := range view.names {
+		view.names[i] = t.Column(i)
+	}
+
+	for from, to := range names {
+		i := findColumn(t, from)
+
+		if i < 0 {
+			return nil, fmt.Errorf("cli.RenamedTableView: no column named %q", from)
+		}
+
+		if column(to).string() == to {
+			to = renameColumn(view.names[i], to)
+		}
+
+		view.names[i] = to
+	}
+
+	return view, nil
+}
+
+func renameColumn(spec string, name string) string {
+	switch column(spec).alignment() {
+	case LeftAlign:
+		return name + ":"
+	case RightAlign:
+		return ":" + name
+	}
+
+	if column(spec).string() != spec {
+		return ":" + name + ":"
+	}
+
+	return name
+}
+
+type renamedTableView struct {
+	TableView
+	names []string
+}
+
+func (t renamedTableView) Column(col int) string {
+	return t.names[col]
+}
+
+func (t renamedTableView) value(col int, row int) (reflect.Value, bool) {
+	return tableCellValue(t.TableView, col, row)
+}
+
+func (t renamedTableView) CellSpan(col int, row int) int {
+	return tableCellSpan(t.TableView, col, row)
+}
+
+func (t renamedTableView) FooterCell(col int, row int) string {
+	return tableFooterCell(t.TableView, col, row)
+}
+
+func (t renamedTableView) FooterSize() int {
+	return tableFooterSize(t.TableView)
+}
+
+func (t renamedTableView) aggregate(col int) (Aggregate, bool) {
+	return tableAggregate(t.TableView, col)
+}
+
+func (t renamedTableView) format(col int, v reflect.Value) string {
+	return formatTableCell(t.TableView, col, v)
+}
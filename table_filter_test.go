@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFilteredTableView(t *testing.T) {
+	type T struct {
+		Name   string `table:"NAME:"`
+		Status string `table:"STATUS:"`
+		Tries  int    `table:":TRIES"`
+	}
+
+	jobs := []T{
+		{"build", "ok", 1},
+		{"test", "failed", 3},
+		{"lint", "ok", 1},
+		{"deploy", "failed", 2},
+	}
+
+	view := FilteredTableView(NewTableView(jobs), func(row int) bool {
+		return jobs[row].Status == "failed"
+	})
+
+	if cols, rows := view.Size(); cols != 3 || rows != 2 {
+		t.Fatalf("invalid table size: %d x %d", cols, rows)
+	}
+
+	if names := tableViewColumn(view, 0); !reflect.DeepEqual(names, []string{"test", "deploy"}) {
+		t.Errorf("invalid rows: %q", names)
+	}
+
+	sorted, err := SortedTableView(view, SortKey{Column: "TRIES"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if names := tableViewColumn(sorted, 0); !reflect.DeepEqual(names, []string{"deploy", "test"}) {
+		t.Errorf("invalid sorted rows: %q", names)
+	}
+}
+
+func TestProjectedTableView(t *testing.T) {
+	table := NewTable("NAME:", ":SIZE", "KIND").
+		Append("hello", "42", "file").
+		Append("world", "1024", "dir")
+
+	view, err := ProjectedTableView(table, "kind", "NAME")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cols, rows := view.Size(); cols != 2 || rows != 2 {
+		t.Fatalf("invalid table size: %d x %d", cols, rows)
+	}
+
+	if view.Column(0) != "KIND" || view.Column(1) != "NAME:" {
+		t.Errorf("invalid columns: %q, %q", view.Column(0), view.Column(1))
+	}
+
+	if cells := tableViewColumn(view, 0); !reflect.DeepEqual(cells, []string{"file", "dir"}) {
+		t.Errorf("invalid cells: %q", cells)
+	}
+
+	if _, err := ProjectedTableView(table, "DATE"); err == nil {
+		t.Error("expected an error when selecting a column that does not exist")
+	}
+}
+
+func TestRenamedTableView(t *testing.T) {
+	table := NewTable("NAME:", ":SIZE", ":KIND:", "DATE").Append("hello", "42", "file", "today")
+
+	view, err := RenamedTableView(table, map[string]string{
+		"NAME": "FILE",
+		"SIZE": "BYTES",
+		"KIND": "TYPE",
+		"DATE": ":WHEN",
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"FILE:", ":BYTES", ":TYPE:", ":WHEN"}
+
+	for i, name := range expected {
+		if col := view.Column(i); col != name {
+			t.Errorf("expected column %d to be %q but found %q", i, name, col)
+		}
+	}
+
+	if _, err := RenamedTableView(table, map[string]string{"OWNER": "USER"}); err == nil {
+		t.Error("expected an error when renaming a column that does not exist")
+	}
+}
@@ -0,0 +1,344 @@
+package cli
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// FooterTableView is implemented by table views that have footer rows, which
+// are rendered under the other rows after a separator line.
+type FooterTableView interface {
+	TableView
+
+	FooterCell(col int, row int) string
+
+	FooterSize() (rows int)
+}
+
+// Aggregate represents functions computing a footer cell from the cells of a
+// column.
+type Aggregate int
+
+const (
+	// SumAggregate is the sum of the numeric cells of a column.
+	SumAggregate Aggregate = iota + 1
+
+	// CountAggregate is the number of non-empty cells of a column.
+	CountAggregate
+
+	// MinAggregate is the minimum of the numeric cells of a column.
+	MinAggregate
+
+	// MaxAggregate is the maximum of the numeric cells of a column.
+	MaxAggregate
+
+	// MeanAggregate is the mean of the numeric cells of a column.
+	MeanAggregate
+)
+
+func parseAggregate(s string) (Aggregate, bool) {
+	switch s {
+	case "sum":
+		return SumAggregate, true
+	case "count":
+		return CountAggregate, true
+	case "min":
+		return MinAggregate, true
+	case "max":
+		return MaxAggregate, true
+	case "mean", "avg":
+		return MeanAggregate, true
+	default:
+		return 0, false
+	}
+}
+
+func (a Aggregate) apply(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	switch a {
+	case SumAggregate, MeanAggregate:
+		sum := 0.0
+
+		for _, v := range values {
+			sum += v
+		}
+
+		if a == MeanAggregate {
+			sum /= float64(len(values))
+		}
+
+		return sum
+
+	case MinAggregate, MaxAggregate:
+		res := values[0]
+
+		for _, v := range values[1:] {
+			if (a == MinAggregate && v < res) || (a == MaxAggregate && v > res) {
+				res = v
+			}
+		}
+
+		return res
+
+	default:
+		return float64(len(values))
+	}
+}
+
+// SummarizedTableView returns a view of t with a footer row holding the
+// aggregates of the columns named in the aggregates map. The aggregates are
+// computed when the function is called, so filtering or sorting should be
+// applied to t before.
+//
+// When t was created by NewTableView from a slice of structs, aggregates are
+// computed from the numeric fields and formatted like the other cells of the
+// column, otherwise cells are parsed as floating point numbers.
+func SummarizedTableView(t TableView, aggregates map[string]Aggregate) (TableView, error) {
+	cols, _ := t.Size()
+	view := summarizedTableView{
+		TableView:  t,
+		footer:     make([]string, cols),
+		aggregates: make([]Aggregate, cols),
+	}
+
+	for name, agg := range aggregates {
+		col := findColumn(t, name)
+
+		if col < 0 {
+			return nil, fmt.Errorf("cli.SummarizedTableView: no column named %q", name)
+		}
+
+		view.footer[col] = aggregateColumn(t, col, agg)
+		view.aggregates[col] = agg
+	}
+
+	return view, nil
+}
+
+type summarizedTableView struct {
+	TableView
+	footer     []string
+	aggregates []Aggregate
+}
+
+func (t summarizedTableView) FooterCell(col int, row int) string {
+	return t.footer[col]
+}
+
+func (t summarizedTableView) FooterSize() int {
+	return 1
+}
+
+func (t summarizedTableView) aggregate(col int) (Aggregate, bool) {
+	return t.aggregates[col], true
+}
+
+func (t summarizedTableView) format(col int, v reflect.Value) string {
+	return formatTableCell(t.TableView, col, v)
+}
+
+func (t summarizedTableView) value(col int, row int) (reflect.Value, bool) {
+	return tableCellValue(t.TableView, col, row)
+}
+
+func (t summarizedTableView) CellSpan(col int, row int) int {
+	return tableCellSpan(t.TableView, col, row)
+}
+
+func tableFooterCell(t TableView, col int, row int) string {
+	if f, ok := t.(FooterTableView); ok {
+		return f.FooterCell(col, row)
+	}
+	return ""
+}
+
+func tableFooterSize(t TableView) int {
+	if f, ok := t.(FooterTableView); ok {
+		return f.FooterSize()
+	}
+	return 0
+}
+
+// aggregateTableView is implemented by table views which footer cells are the
+// aggregates of their columns, so views which change the rows can compute the
+// footer again. The aggregate is zero for columns which are not aggregated.
+type aggregateTableView interface {
+	aggregate(col int) (Aggregate, bool)
+}
+
+func tableAggregate(t TableView, col int) (Aggregate, bool) {
+	if a, ok := t.(aggregateTableView); ok {
+		return a.aggregate(col)
+	}
+	return 0, false
+}
+
+// footerCache holds the footer cells computed from aggregates, renderers read
+// each cell more than once.
+type footerCache struct {
+	mutex sync.Mutex
+	cells map[int]string
+}
+
+func (c *footerCache) get(col int, compute func() string) string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	s, ok := c.cells[col]
+
+	if !ok {
+		if c.cells == nil {
+			c.cells = make(map[int]string)
+		}
+		s = compute()
+		c.cells[col] = s
+	}
+
+	return s
+}
+
+// formatTableView is implemented by table views which format values into cells
+// and can therefore format aggregates the same way.
+type formatTableView interface {
+	format(col int, v reflect.Value) string
+}
+
+func formatTableCell(t TableView, col int, v reflect.Value) string {
+	if f, ok := t.(formatTableView); ok {
+		return f.format(col, v)
+	}
+	f := makeDefaultCellFormat()
+	return f.format(v)
+}
+
+func aggregateColumn(t TableView, col int, agg Aggregate) string {
+	_, rows := t.Size()
+	values := make([]float64, 0, rows)
+	decimals := aggregatePrecision
+	var typ reflect.Type
+
+	for j := 0; j != rows; j++ {
+		if v, ok := tableCellValue(t, col, j); ok {
+			for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+				if v.IsNil() {
+					break
+				}
+				v = v.Elem()
+			}
+
+			if f, ok := numericValue(v); ok {
+				values, typ = append(values, f), v.Type()
+			} else if agg == CountAggregate && (v.Kind() != reflect.Ptr && v.Kind() != reflect.Interface) {
+				values = append(values, 0)
+			}
+
+			continue
+		}
+
+		cell := StripStylesInString(t.Cell(col, j))
+
+		if f, err := strconv.ParseFloat(cell, 64); err == nil {
+			values = append(values, f)
+
+			if i := strings.IndexByte(cell, '.'); i >= 0 && len(cell)-i-1 > decimals {
+				decimals = len(cell) - i - 1
+			}
+		} else if agg == CountAggregate && len(cell) != 0 {
+			values = append(values, 0)
+		}
+	}
+
+	res := agg.apply(values)
+
+	if agg == CountAggregate {
+		return strconv.Itoa(int(res))
+	}
+
+	if f, ok := t.(formatTableView); ok && typ != nil {
+		return f.format(col, convertAggregate(res, typ))
+	}
+
+	return formatAggregate(res, decimals)
+}
+
+// aggregatePrecision is the maximum number of decimals of aggregates which are
+// not integers, unless the column has more precise values or a precision option.
+const aggregatePrecision = 2
+
+// formatAggregate formats f with at most the given number of decimals.
+func formatAggregate(f float64, decimals int) string {
+	s := strconv.FormatFloat(f, 'f', decimals, 64)
+
+	if strings.IndexByte(s, '.') >= 0 {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+
+	return s
+}
+
+// aggregateFloat is the type of aggregates of integer columns which are not
+// integers, like the mean of 1 and 2. The precision option of the column
+// applies to it, otherwise it is formatted with aggregatePrecision decimals.
+type aggregateFloat float64
+
+func (f aggregateFloat) String() string {
+	return formatAggregate(float64(f), aggregatePrecision)
+}
+
+func numericValue(v reflect.Value) (float64, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	default:
+		return 0, false
+	}
+}
+
+// convertAggregate converts the aggregate f to a value of type t. Floating
+// point values are kept when converting to an integer type would truncate the
+// aggregate, unless the type has methods (like time.Duration) which likely
+// define how it is formatted.
+func convertAggregate(f float64, t reflect.Type) reflect.Value {
+	switch t.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return reflect.ValueOf(f).Convert(t)
+	}
+
+	if f != math.Trunc(f) && t.NumMethod() == 0 {
+		return reflect.ValueOf(aggregateFloat(f))
+	}
+
+	switch t.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return reflect.ValueOf(uint64(math.Round(f))).Convert(t)
+	default:
+		return reflect.ValueOf(int64(math.Round(f))).Convert(t)
+	}
+}
+
+// footerTableView adapts the footer of a table view to the TableView interface
+// so it can be laid out and rendered like regular rows.
+type footerTableView struct {
+	FooterTableView
+}
+
+func (t footerTableView) Cell(col int, row int) string {
+	return t.FooterTableView.FooterCell(col, row)
+}
+
+func (t footerTableView) Size() (cols int, rows int) {
+	cols, _ = t.FooterTableView.Size()
+	rows = t.FooterTableView.FooterSize()
+	return
+}
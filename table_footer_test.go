@@ -0,0 +1,324 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestRenderTableFooter(t *testing.T) {
+	table := NewTable("NAME:", ":SIZE").
+		Append("hello", "42").
+		Append("world", "1024").
+		AppendFooter("TOTAL", "1066")
+
+	tests := []struct {
+		name   string
+		border TableBorder
+		output string
+	}{
+		{
+			name:   "No Border",
+			border: NoBorder,
+			output: "" +
+				"NAME  SIZE\n" +
+				"hello   42\n" +
+				"world 1024\n" +
+				"───── ────\n" +
+				"TOTAL 1066\n",
+		},
+		{
+			name:   "Light",
+			border: LightBorder,
+			output: "" +
+				"┌───────┬──────┐\n" +
+				"│ NAME  │ SIZE │\n" +
+				"├───────┼──────┤\n" +
+				"│ hello │   42 │\n" +
+				"│ world │ 1024 │\n" +
+				"├───────┼──────┤\n" +
+				"│ TOTAL │ 1066 │\n" +
+				"└───────┴──────┘\n",
+		},
+	}
+
+	buffer := &bytes.Buffer{}
+
+	for _, test := range tests {
+		buffer.Reset()
+
+		if err := RenderTableViewWithOptions(buffer, table, TableOptions{Border: &test.border}); err != nil {
+			t.Errorf("%s: %s", test.name, err)
+		} else if s := buffer.String(); s != test.output {
+			t.Errorf("%s:\n\n%s\n%q", test.name, s, s)
+		}
+	}
+}
+
+func TestSummarizedTableView(t *testing.T) {
+	table := NewTable("NAME:", ":SIZE", ":SCORE").
+		Append("a", "10", "1.5").
+		Append("b", "20", "").
+		Append("c", "30", "2.5")
+
+	view, err := SummarizedTableView(table, map[string]Aggregate{
+		"NAME":  CountAggregate,
+		"SIZE":  SumAggregate,
+		"SCORE": MeanAggregate,
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	footer := view.(FooterTableView)
+	expected := []string{"3", "60", "2"}
+
+	for i, cell := range expected {
+		if s := footer.FooterCell(i, 0); s != cell {
+			t.Errorf("%s: expected %q but found %q", view.Column(i), cell, s)
+		}
+	}
+
+	if _, err := SummarizedTableView(table, map[string]Aggregate{"DATE": MaxAggregate}); err == nil {
+		t.Error("expected an error when summarizing a column that does not exist")
+	}
+}
+
+func TestTableViewFooterTag(t *testing.T) {
+	type T struct {
+		Name    string        `table:"NAME:,footer=count"`
+		Size    int64         `table:":SIZE,humanize=bytes,footer=sum"`
+		Elapsed time.Duration `table:":ELAPSED,footer=mean"`
+		Ratio   float64       `table:":RATIO,precision=2,footer=max"`
+		Tries   int           `table:":TRIES,footer=mean"`
+	}
+
+	view := NewTableView([]T{
+		{"a", 1024, time.Second, 0.5, 1},
+		{"b", 2048, 2 * time.Second, 0.25, 2},
+	}).(FooterTableView)
+
+	if n := view.FooterSize(); n != 1 {
+		t.Fatalf("expected one footer row but found %d", n)
+	}
+
+	expected := []string{"2", "3.0 KiB", "1.5s", "0.50", "1.5"}
+
+	for i, cell := range expected {
+		if s := view.FooterCell(i, 0); s != cell {
+			t.Errorf("%s: expected %q but found %q", view.Column(i), cell, s)
+		}
+	}
+
+}
+
+func TestTableViewFooterPrecision(t *testing.T) {
+	type T struct {
+		Tries int `table:":TRIES,footer=mean"`
+		Load  int `table:":LOAD,precision=3,footer=mean"`
+	}
+
+	view := NewTableView([]T{{1, 1}, {2, 2}, {2, 2}}).(FooterTableView)
+
+	for i, cell := range []string{"1.67", "1.667"} {
+		if s := view.FooterCell(i, 0); s != cell {
+			t.Errorf("%s: expected %q but found %q", view.Column(i), cell, s)
+		}
+	}
+
+	table := NewTable(":A", ":B").
+		Append("1", "0.125").
+		Append("2", "0.5").
+		Append("2", "1")
+
+	summarized, err := SummarizedTableView(table, map[string]Aggregate{
+		"A": MeanAggregate,
+		"B": SumAggregate,
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	footer := summarized.(FooterTableView)
+
+	for i, cell := range []string{"1.67", "1.625"} {
+		if s := footer.FooterCell(i, 0); s != cell {
+			t.Errorf("%s: expected %q but found %q", summarized.Column(i), cell, s)
+		}
+	}
+}
+
+func TestRenderTableFooterASCII(t *testing.T) {
+	table := NewTable("NAME:", ":SIZE").
+		Append("hello", "42").
+		AppendFooter("TOTAL", "42")
+
+	b := &sizedWriter{}
+
+	if err := RenderTableView(readWriter{Writer: b, ascii: true}, table); err != nil {
+		t.Fatal(err)
+	}
+
+	const output = "" +
+		"NAME  SIZE\n" +
+		"hello   42\n" +
+		"----- ----\n" +
+		"TOTAL   42\n"
+
+	if s := b.String(); s != output {
+		t.Errorf("\n\n%s\n%q", s, s)
+	}
+}
+
+func TestTableViewFooterCache(t *testing.T) {
+	type T struct {
+		Size int `table:":SIZE,footer=sum"`
+	}
+
+	rows := []T{{1}, {2}}
+	view := NewTableView(rows).(FooterTableView)
+
+	if s := view.FooterCell(0, 0); s != "3" {
+		t.Fatalf("expected a sum of 3 but found %s", s)
+	}
+
+	// The aggregate is computed once, changes to the rows after the first
+	// call are not seen.
+	rows[0].Size = 10
+
+	if s := view.FooterCell(0, 0); s != "3" {
+		t.Errorf("expected the cached sum of 3 but found %s", s)
+	}
+}
+
+func TestWrappedTableViewFooter(t *testing.T) {
+	type T struct {
+		Name string `table:"NAME:,footer=count"`
+		Size int    `table:":SIZE,footer=sum"`
+	}
+
+	view := NewTableView([]T{
+		{"a", 10},
+		{"b", 20},
+		{"c", 30},
+	})
+
+	static := NewTable("NAME:", ":SIZE").
+		Append("a", "10").
+		Append("b", "20").
+		AppendFooter("TOTAL", "30")
+
+	tests := []struct {
+		name   string
+		view   func() (TableView, error)
+		output string
+	}{
+		{
+			name: "Sorted",
+			view: func() (TableView, error) {
+				return SortedTableView(view, ParseSortKeys("-SIZE")...)
+			},
+			output: "" +
+				"NAME SIZE\n" +
+				"c      30\n" +
+				"b      20\n" +
+				"a      10\n" +
+				"──── ────\n" +
+				"3      60\n",
+		},
+		{
+			name: "Filtered",
+			view: func() (TableView, error) {
+				return FilteredTableView(view, func(row int) bool { return row != 1 }), nil
+			},
+			output: "" +
+				"NAME SIZE\n" +
+				"a      10\n" +
+				"c      30\n" +
+				"──── ────\n" +
+				"2      40\n",
+		},
+		{
+			name: "Filtered Static",
+			view: func() (TableView, error) {
+				return FilteredTableView(static, func(row int) bool { return row != 1 }), nil
+			},
+			output: "" +
+				"NAME SIZE\n" +
+				"a      10\n",
+		},
+		{
+			name: "Projected",
+			view: func() (TableView, error) {
+				return ProjectedTableView(view, "SIZE", "NAME")
+			},
+			output: "" +
+				"SIZE NAME\n" +
+				"  10 a   \n" +
+				"  20 b   \n" +
+				"  30 c   \n" +
+				"──── ────\n" +
+				"  60 3   \n",
+		},
+		{
+			name: "Renamed",
+			view: func() (TableView, error) {
+				return RenamedTableView(view, map[string]string{"SIZE": "BYTES"})
+			},
+			output: "" +
+				"NAME BYTES\n" +
+				"a       10\n" +
+				"b       20\n" +
+				"c       30\n" +
+				"──── ─────\n" +
+				"3       60\n",
+		},
+		{
+			name: "Styled",
+			view: func() (TableView, error) {
+				return StyledTableView(view, Bold), nil
+			},
+			output: "" +
+				"\x1b[1mNAME\x1b[0m \x1b[1mSIZE\x1b[0m\n" +
+				"a      10\n" +
+				"b      20\n" +
+				"c      30\n" +
+				"──── ────\n" +
+				"3      60\n",
+		},
+		{
+			name: "Cell Styled",
+			view: func() (TableView, error) {
+				return CellStyledTableView(view, func(int, int, string) StyleSet { return nil }), nil
+			},
+			output: "" +
+				"NAME SIZE\n" +
+				"a      10\n" +
+				"b      20\n" +
+				"c      30\n" +
+				"──── ────\n" +
+				"3      60\n",
+		},
+	}
+
+	buffer := &bytes.Buffer{}
+
+	for _, test := range tests {
+		buffer.Reset()
+
+		v, err := test.view()
+
+		if err != nil {
+			t.Errorf("%s: %s", test.name, err)
+			continue
+		}
+
+		if err := RenderTableView(buffer, v); err != nil {
+			t.Errorf("%s: %s", test.name, err)
+		} else if s := buffer.String(); s != test.output {
+			t.Errorf("%s:\n\n%s\n%q", test.name, s, s)
+		}
+	}
+}
@@ -0,0 +1,321 @@
+package cli
+
+import (
+	"encoding"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	cellFormattersMutex sync.RWMutex
+	cellFormatters      = map[reflect.Type]func(interface{}) string{}
+)
+
+// RegisterCellFormatter sets the function used to format the cells of table
+// views created by NewTableView that hold values of type t. Passing a nil
+// function removes the formatter registered for t.
+func RegisterCellFormatter(t reflect.Type, format func(interface{}) string) {
+	cellFormattersMutex.Lock()
+	defer cellFormattersMutex.Unlock()
+
+	if format == nil {
+		delete(cellFormatters, t)
+	} else {
+		cellFormatters[t] = format
+	}
+}
+
+func lookupCellFormatter(t reflect.Type) func(interface{}) string {
+	cellFormattersMutex.RLock()
+	defer cellFormattersMutex.RUnlock()
+	return cellFormatters[t]
+}
+
+// tableTag is the parsed representation of a `table:"..."` struct tag, made of
+// a column name followed by comma-separated options like "precision=2". Values
+// which contain commas are quoted with single quotes, like "format='Jan 2, 2006'".
+type tableTag struct {
+	name    string
+	options map[string]string
+}
+
+func parseTableTag(tag string) (t tableTag) {
+	parts := splitTableTag(tag)
+	t.name = parts[0]
+
+	for _, p := range parts[1:] {
+		if p = strings.TrimSpace(p); len(p) == 0 {
+			continue
+		}
+
+		k, v := p, ""
+
+		if i := strings.IndexByte(p, '='); i >= 0 {
+			k, v = p[:i], p[i+1:]
+		}
+
+		if n := len(v); n >= 2 && v[0] == '\'' && v[n-1] == '\'' {
+			v = v[1 : n-1]
+		}
+
+		if t.options == nil {
+			t.options = make(map[string]string)
+		}
+
+		t.options[k] = v
+	}
+
+	return
+}
+
+// splitTableTag splits tag on the commas which are not within single quotes.
+func splitTableTag(tag string) (parts []string) {
+	quoted, i := false, 0
+
+	for j := 0; j != len(tag); j++ {
+		switch tag[j] {
+		case '\'':
+			quoted = !quoted
+		case ',':
+			if !quoted {
+				parts, i = append(parts, tag[i:j]), j+1
+			}
+		}
+	}
+
+	return append(parts, tag[i:])
+}
+
+// tableTagOptions is the set of options supported in `table:"..."` tags.
+var tableTagOptions = map[string]bool{
+	"default":   true,
+	"field":     true,
+	"fields":    true,
+	"footer":    true,
+	"format":    true,
+	"humanize":  true,
+	"inline":    true,
+	"precision": true,
+	"prefix":    true,
+	"style":     true,
+}
+
+// check returns an error if the tag has options which are not supported, which
+// is most likely a typo or an unquoted value containing a comma.
+func (t tableTag) check(field reflect.StructField) error {
+	keys := make([]string, 0, len(t.options))
+
+	for k := range t.options {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if !tableTagOptions[k] {
+			return fmt.Errorf("unknown option %q on field %s, values containing commas must be quoted with single quotes", k, field.Name)
+		}
+	}
+
+	return nil
+}
+
+func (t tableTag) lookup(key string) (value string, ok bool) {
+	value, ok = t.options[key]
+	return
+}
+
+// cellFormat holds the formatting options of a column of a struct-backed table
+// view, set by the format, precision, humanize and default tag options.
+type cellFormat struct {
+	layout    string
+	precision int
+	humanize  string
+	defval    string
+}
+
+func makeDefaultCellFormat() cellFormat {
+	return cellFormat{precision: -1}
+}
+
+func makeCellFormat(field reflect.StructField, tag tableTag) (f cellFormat, err error) {
+	f = makeDefaultCellFormat()
+
+	if err = tag.check(field); err != nil {
+		return
+	}
+
+	f.layout, _ = tag.lookup("format")
+	f.defval, _ = tag.lookup("default")
+
+	if h, ok := tag.lookup("humanize"); ok {
+		switch h {
+		case "bytes", "si":
+			f.humanize = h
+		default:
+			err = fmt.Errorf("invalid humanize option %q on field %s, expected bytes or si", h, field.Name)
+			return
+		}
+	}
+
+	if p, ok := tag.lookup("precision"); ok {
+		n, e := strconv.Atoi(p)
+		if e != nil || n < 0 {
+			err = fmt.Errorf("invalid precision option %q on field %s", p, field.Name)
+			return
+		}
+		f.precision = n
+	}
+
+	return
+}
+
+func (f *cellFormat) format(v reflect.Value) string {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return f.defval
+		}
+		v = v.Elem()
+	}
+
+	if !v.IsValid() || isZeroCell(v) {
+		return f.defval
+	}
+
+	cell := f.formatValue(v)
+
+	if len(cell) == 0 {
+		cell = f.defval
+	}
+
+	return cell
+}
+
+// isZeroCell returns true if v reports being a zero value through an IsZero
+// method, like time.Time does. Such values are rendered as empty cells, plain
+// zero numbers are not since they are often meaningful.
+func isZeroCell(v reflect.Value) bool {
+	if !v.CanInterface() {
+		return false
+	}
+	z, ok := v.Interface().(interface {
+		IsZero() bool
+	})
+	return ok && z.IsZero()
+}
+
+func (f *cellFormat) formatValue(v reflect.Value) string {
+	if format := lookupCellFormatter(v.Type()); format != nil {
+		return format(v.Interface())
+	}
+
+	if t, ok := v.Interface().(time.Time); ok {
+		if len(f.layout) == 0 {
+			return t.Format(time.RFC3339)
+		}
+		return t.Format(f.layout)
+	}
+
+	if len(f.humanize) != 0 {
+		if s, ok := f.formatHumanized(v); ok {
+			return s
+		}
+	}
+
+	if f.precision >= 0 {
+		switch v.Kind() {
+		case reflect.Float32, reflect.Float64:
+			return strconv.FormatFloat(v.Float(), 'f', f.precision, v.Type().Bits())
+		}
+	}
+
+	if strings.ContainsRune(f.layout, '%') {
+		return fmt.Sprintf(f.layout, v.Interface())
+	}
+
+	x := v.Interface()
+
+	if v.CanAddr() {
+		// Look for methods with a pointer receiver as well.
+		switch p := v.Addr().Interface().(type) {
+		case fmt.Stringer, encoding.TextMarshaler:
+			x = p
+		}
+	}
+
+	switch x := x.(type) {
+	case fmt.Stringer:
+		return x.String()
+	case encoding.TextMarshaler:
+		if b, err := x.MarshalText(); err == nil {
+			return string(b)
+		}
+	}
+
+	return fmt.Sprint(v.Interface())
+}
+
+func (f *cellFormat) formatHumanized(v reflect.Value) (string, bool) {
+	var n float64
+
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n = float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n = float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		n = v.Float()
+	default:
+		return "", false
+	}
+
+	precision := f.precision
+
+	if precision < 0 {
+		precision = 1
+	}
+
+	switch f.humanize {
+	case "bytes":
+		return humanize(n, 1024, precision, " ", []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}), true
+	default:
+		return humanize(n, 1000, precision, "", []string{"", "k", "M", "G", "T", "P", "E"}), true
+	}
+}
+
+func humanize(n float64, base float64, precision int, sep string, units []string) string {
+	i := 0
+
+	for math.Abs(n) >= base && (i+1) < len(units) {
+		n /= base
+		i++
+	}
+
+	if i == 0 {
+		precision = 0
+	}
+
+	return strconv.FormatFloat(n, 'f', precision, 64) + sep + units[i]
+}
+
+func lookupAggregate(field reflect.StructField, tag tableTag) (Aggregate, error) {
+	name, ok := tag.lookup("footer")
+
+	if !ok {
+		return 0, nil
+	}
+
+	agg, ok := parseAggregate(name)
+
+	if !ok {
+		return 0, fmt.Errorf("invalid footer option %q on field %s, expected sum, count, min, max or mean", name, field.Name)
+	}
+
+	return agg, nil
+}
@@ -0,0 +1,211 @@
+package cli
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/achille-roussel/cli/internal/compare"
+)
+
+// SortKey represents a column that a table is sorted by.
+type SortKey struct {
+	// Column is the name of the column, without alignment markers.
+	Column string
+
+	// Descending reverses the sort order.
+	Descending bool
+}
+
+// ParseSortKeys parses a comma-separated list of column names into sort keys,
+// a column name prefixed with '-' sorts in descending order (e.g. "NAME,-SIZE").
+func ParseSortKeys(s string) []SortKey {
+	var keys []SortKey
+
+	for _, name := range strings.Split(s, ",") {
+		if name = strings.TrimSpace(name); len(name) == 0 {
+			continue
+		}
+
+		key := SortKey{Column: name}
+
+		switch name[0] {
+		case '-':
+			key.Column, key.Descending = name[1:], true
+		case '+':
+			key.Column = name[1:]
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// SortedTableView returns a view of t with rows sorted by the given keys. The
+// sort is stable, rows that compare equal on all keys keep their order.
+//
+// When t was created by NewTableView from a slice of structs, cells are
+// compared using the types of the fields (numbers, times and durations are
+// compared by value), otherwise cells are compared in natural order, with
+// numbers embedded in the strings compared numerically.
+func SortedTableView(t TableView, keys ...SortKey) (TableView, error) {
+	_, rows := t.Size()
+	view := sortedTableView{
+		TableView: t,
+		rows:      make([]int, rows),
+	}
+
+	for j := range view.rows {
+		view.rows[j] = j
+	}
+
+	cmps := make([]func(int, int) int, len(keys))
+
+	for k, key := range keys {
+		col := findColumn(t, key.Column)
+
+		if col < 0 {
+			return nil, fmt.Errorf("cli.SortedTableView: no column named %q", key.Column)
+		}
+
+		cmp := makeColumnComparator(t, col, rows)
+
+		if key.Descending {
+			cmps[k] = func(a, b int) int { return -cmp(a, b) }
+		} else {
+			cmps[k] = cmp
+		}
+	}
+
+	sort.SliceStable(view.rows, func(a, b int) bool {
+		for _, cmp := range cmps {
+			if c := cmp(view.rows[a], view.rows[b]); c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+
+	return view, nil
+}
+
+type sortedTableView struct {
+	TableView
+	rows []int
+}
+
+func (t sortedTableView) Cell(col int, row int) string {
+	return t.TableView.Cell(col, t.rows[row])
+}
+
+func (t sortedTableView) value(col int, row int) (reflect.Value, bool) {
+	return tableCellValue(t.TableView, col, t.rows[row])
+}
+
+func (t sortedTableView) CellSpan(col int, row int) int {
+	return tableCellSpan(t.TableView, col, t.rows[row])
+}
+
+func (t sortedTableView) FooterCell(col int, row int) string {
+	return tableFooterCell(t.TableView, col, row)
+}
+
+func (t sortedTableView) FooterSize() int {
+	return tableFooterSize(t.TableView)
+}
+
+func (t sortedTableView) aggregate(col int) (Aggregate, bool) {
+	return tableAggregate(t.TableView, col)
+}
+
+func (t sortedTableView) format(col int, v reflect.Value) string {
+	return formatTableCell(t.TableView, col, v)
+}
+
+// valueTableView is implemented by table views which give access to the values
+// that their cells are made of.
+type valueTableView interface {
+	value(col int, row int) (reflect.Value, bool)
+}
+
+func tableCellValue(t TableView, col int, row int) (reflect.Value, bool) {
+	if v, ok := t.(valueTableView); ok {
+		return v.value(col, row)
+	}
+	return reflect.Value{}, false
+}
+
+func makeColumnComparator(t TableView, col int, rows int) func(int, int) int {
+	cells := make([]string, rows)
+
+	for j := range cells {
+		cells[j] = StripStylesInString(t.Cell(col, j))
+	}
+
+	return func(a, b int) int {
+		if va, ok := tableCellValue(t, col, a); ok {
+			if vb, ok := tableCellValue(t, col, b); ok {
+				if c, ok := compareValues(va, vb); ok {
+					return c
+				}
+			}
+		}
+		return compare.NaturalFold(cells[a], cells[b])
+	}
+}
+
+// compareValues compares two values of the same type, returning false if the
+// type has no natural ordering.
+func compareValues(a reflect.Value, b reflect.Value) (int, bool) {
+	for a.Kind() == reflect.Ptr || a.Kind() == reflect.Interface {
+		if a.Kind() != b.Kind() {
+			return 0, false
+		}
+
+		switch an, bn := a.IsNil(), b.IsNil(); {
+		case an && bn:
+			return 0, true
+		case an:
+			return -1, true
+		case bn:
+			return 1, true
+		}
+
+		a, b = a.Elem(), b.Elem()
+	}
+
+	if a.Type() != b.Type() {
+		return 0, false
+	}
+
+	if ta, ok := a.Interface().(time.Time); ok {
+		tb := b.Interface().(time.Time)
+
+		switch {
+		case ta.Before(tb):
+			return -1, true
+		case ta.After(tb):
+			return 1, true
+		default:
+			return 0, true
+		}
+	}
+
+	switch a.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return compare.Ordered(a.Int() < b.Int(), a.Int() > b.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return compare.Ordered(a.Uint() < b.Uint(), a.Uint() > b.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return compare.Ordered(a.Float() < b.Float(), a.Float() > b.Float()), true
+	case reflect.Bool:
+		return compare.Ordered(!a.Bool() && b.Bool(), a.Bool() && !b.Bool()), true
+	case reflect.String:
+		return compare.NaturalFold(a.String(), b.String()), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func tableViewColumn(t TableView, col int) []string {
+	_, rows := t.Size()
+	cells := make([]string, rows)
+
+	for j := range cells {
+		cells[j] = t.Cell(col, j)
+	}
+
+	return cells
+}
+
+func TestSortedTableView(t *testing.T) {
+	table := NewTable("NAME", "SIZE").
+		Append("file10", "3").
+		Append("File2", "1").
+		Append("file1", "2").
+		Append("file2", "1")
+
+	tests := []struct {
+		keys  []SortKey
+		names []string
+	}{
+		{
+			keys:  ParseSortKeys("NAME"),
+			names: []string{"file1", "File2", "file2", "file10"},
+		},
+		{
+			keys:  ParseSortKeys("-name"),
+			names: []string{"file10", "file2", "File2", "file1"},
+		},
+		{
+			keys:  ParseSortKeys("SIZE,-NAME"),
+			names: []string{"file2", "File2", "file1", "file10"},
+		},
+		{
+			keys:  nil,
+			names: []string{"file10", "File2", "file1", "file2"},
+		},
+	}
+
+	for _, test := range tests {
+		view, err := SortedTableView(table, test.keys...)
+
+		if err != nil {
+			t.Error(err)
+		} else if names := tableViewColumn(view, 0); !reflect.DeepEqual(names, test.names) {
+			t.Errorf("%v: expected %q but found %q", test.keys, test.names, names)
+		}
+	}
+
+	if _, err := SortedTableView(table, SortKey{Column: "DATE"}); err == nil {
+		t.Error("expected an error when sorting by a column that does not exist")
+	}
+}
+
+func TestSortedTableViewFromStruct(t *testing.T) {
+	type T struct {
+		Name    string        `table:"NAME:"`
+		Size    int           `table:":SIZE"`
+		Elapsed time.Duration `table:"ELAPSED"`
+		Date    time.Time     `table:"DATE,format=Jan 2"`
+	}
+
+	now := time.Date(2017, 6, 24, 0, 0, 0, 0, time.UTC)
+	view := NewTableView([]T{
+		{"A", 100, 2 * time.Second, now.AddDate(0, 1, 0)},
+		{"B", 20, 90 * time.Millisecond, now},
+		{"C", 3, time.Minute, now.AddDate(1, 0, 0)},
+	})
+
+	tests := []struct {
+		keys  []SortKey
+		names []string
+	}{
+		{ParseSortKeys("SIZE"), []string{"C", "B", "A"}},
+		{ParseSortKeys("-SIZE"), []string{"A", "B", "C"}},
+		{ParseSortKeys("ELAPSED"), []string{"B", "A", "C"}},
+		{ParseSortKeys("DATE"), []string{"B", "A", "C"}},
+	}
+
+	for _, test := range tests {
+		sorted, err := SortedTableView(view, test.keys...)
+
+		if err != nil {
+			t.Error(err)
+		} else if names := tableViewColumn(sorted, 0); !reflect.DeepEqual(names, test.names) {
+			t.Errorf("%v: expected %q but found %q", test.keys, test.names, names)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package cli
+
+// SpanTableView is implemented by table views which have cells spanning
+// multiple columns. The cells covered by a spanning cell are not rendered.
+type SpanTableView interface {
+	TableView
+
+	// CellSpan returns the number of columns covered by the cell at col and
+	// row, values less than one are treated as one.
+	CellSpan(col int, row int) int
+}
+
+func tableCellSpan(t TableView, col int, row int) int {
+	if s, ok := t.(SpanTableView); ok {
+		if n := s.CellSpan(col, row); n > 1 {
+			return n
+		}
+	}
+	return 1
+}
+
+// tableColumnGroups returns the groups of each column of t, as defined by the
+// "[GROUP]NAME" syntax of column specs.
+func tableColumnGroups(t TableView, cols int) (groups [][]string, depth int) {
+	groups = make([][]string, cols)
+
+	for i := range groups {
+		groups[i] = column(StripStylesInString(t.Column(i))).groups()
+
+		if n := len(groups[i]); n > depth {
+			depth = n
+		}
+	}
+
+	return
+}
+
+// groupSpan returns the number of consecutive columns, starting at col, that
+// belong to the same group at the given header level. Columns which are not
+// grouped at this level span a single column.
+func groupSpan(groups [][]string, level int, col int) int {
+	if level >= len(groups[col]) {
+		return 1
+	}
+
+	span := 1
+
+	for col+span < len(groups) && sameGroup(groups[col], groups[col+span], level) {
+		span++
+	}
+
+	return span
+}
+
+func sameGroup(a []string, b []string, level int) bool {
+	if level >= len(a) || level >= len(b) {
+		return false
+	}
+
+	for i := 0; i <= level; i++ {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// widenTableColumns evenly grows the span columns starting at col so a cell of
+// the given width fits across all of them.
+func widenTableColumns(widths []int, border *TableBorder, col int, span int, width int) {
+	last := col + span - 1
+
+	if last >= len(widths) {
+		last = len(widths) - 1
+	}
+
+	total := 0
+
+	for i := col; i <= last; i++ {
+		if i != col {
+			total += DisplayWidthInString(border.Separator) + 2*border.Padding
+		}
+		total += widths[i]
+	}
+
+	if extra := width - total; extra > 0 {
+		n := last - col + 1
+
+		for i := col; i <= last; i++ {
+			widths[i] += extra / n
+
+			if last-i < extra%n {
+				widths[i]++
+			}
+		}
+	}
+}
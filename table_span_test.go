@@ -0,0 +1,185 @@
+package cli
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestRenderTableGroupedColumns(t *testing.T) {
+	table := NewTable("NAME:", ":[LATENCY]P50", ":[LATENCY]P95", ":[LATENCY]P99").
+		Append("api", "12ms", "48ms", "120ms").
+		Append("db", "3ms", "9ms", "30ms")
+
+	tests := []struct {
+		name   string
+		border TableBorder
+		output string
+	}{
+		{
+			name:   "No Border",
+			border: NoBorder,
+			output: "" +
+				"         LATENCY    \n" +
+				"NAME  P50  P95   P99\n" +
+				"api  12ms 48ms 120ms\n" +
+				"db    3ms  9ms  30ms\n",
+		},
+		{
+			name:   "Light",
+			border: LightBorder,
+			output: "" +
+				"┌──────┬─────────────────────┐\n" +
+				"│      │       LATENCY       │\n" +
+				"│ NAME │  P50 │  P95 │   P99 │\n" +
+				"├──────┼──────┼──────┼───────┤\n" +
+				"│ api  │ 12ms │ 48ms │ 120ms │\n" +
+				"│ db   │  3ms │  9ms │  30ms │\n" +
+				"└──────┴──────┴──────┴───────┘\n",
+		},
+	}
+
+	buffer := &bytes.Buffer{}
+
+	for _, test := range tests {
+		buffer.Reset()
+
+		if err := RenderTableViewWithOptions(buffer, table, TableOptions{Border: &test.border}); err != nil {
+			t.Errorf("%s: %s", test.name, err)
+		} else if s := buffer.String(); s != test.output {
+			t.Errorf("%s:\n\n%s\n%q", test.name, s, s)
+		}
+	}
+}
+
+func TestRenderTableCellSpans(t *testing.T) {
+	table := NewTable("NAME:", "STATUS:", ":TIME").
+		Append("build", "ok", "12s").
+		Append("deploy", "skipped because build is not tagged", "").
+		SetCellSpan(1, 1, 2)
+
+	tests := []struct {
+		name   string
+		border TableBorder
+		output string
+	}{
+		{
+			name:   "No Border",
+			border: NoBorder,
+			output: "" +
+				"NAME   STATUS                         TIME\n" +
+				"build  ok                              12s\n" +
+				"deploy skipped because build is not tagged\n",
+		},
+		{
+			name:   "ASCII",
+			border: ASCIIBorder,
+			output: "" +
+				"+--------+-------------------+-----------------+\n" +
+				"| NAME   | STATUS            |            TIME |\n" +
+				"+--------+-------------------+-----------------+\n" +
+				"| build  | ok                |             12s |\n" +
+				"| deploy | skipped because build is not tagged |\n" +
+				"+--------+-------------------------------------+\n",
+		},
+	}
+
+	buffer := &bytes.Buffer{}
+
+	for _, test := range tests {
+		buffer.Reset()
+
+		if err := RenderTableViewWithOptions(buffer, table, TableOptions{Border: &test.border}); err != nil {
+			t.Errorf("%s: %s", test.name, err)
+		} else if s := buffer.String(); s != test.output {
+			t.Errorf("%s:\n\n%s\n%q", test.name, s, s)
+		}
+	}
+}
+
+func TestWrappedTableViewCellSpans(t *testing.T) {
+	table := NewTable("A", "B", "C").
+		Append("1", "2", "3").
+		Append("merged", "", "4").
+		SetCellSpan(0, 1, 2)
+
+	project := func(names ...string) TableView {
+		view, err := ProjectedTableView(table, names...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return view
+	}
+
+	summarized, err := SummarizedTableView(table, map[string]Aggregate{"C": SumAggregate})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		view  TableView
+		spans []int
+	}{
+		{"Summarized", summarized, []int{2, 1, 1}},
+		{"Identity Projection", project("A", "B", "C"), []int{2, 1, 1}},
+		{"Contiguous Projection", project("A", "B"), []int{2, 1}},
+		{"Cut Projection", project("A", "C"), []int{1, 1}},
+		{"Reordered Projection", project("B", "A", "C"), []int{1, 1, 1}},
+	}
+
+	for _, test := range tests {
+		cols, _ := test.view.Size()
+		spans := make([]int, cols)
+
+		for i := range spans {
+			spans[i] = tableCellSpan(test.view, i, 1)
+		}
+
+		if !reflect.DeepEqual(spans, test.spans) {
+			t.Errorf("%s: expected spans %v but found %v", test.name, test.spans, spans)
+		}
+	}
+
+	buffer := &bytes.Buffer{}
+	border := ASCIIBorder
+
+	if err := RenderTableViewWithOptions(buffer, project("A", "B"), TableOptions{Border: &border}); err != nil {
+		t.Fatal(err)
+	}
+
+	const output = "" +
+		"+---+----+\n" +
+		"| A | B  |\n" +
+		"+---+----+\n" +
+		"| 1 | 2  |\n" +
+		"| merged |\n" +
+		"+--------+\n"
+
+	if s := buffer.String(); s != output {
+		t.Errorf("\n\n%s\n%q", s, s)
+	}
+}
+
+func TestGroupedColumnNames(t *testing.T) {
+	table := NewTable("NAME", ":[LATENCY]P50")
+
+	if i := findColumn(table, "p50"); i != 1 {
+		t.Error("column found by name at wrong index:", i)
+	}
+
+	if i := findColumn(table, "[latency]p50"); i != 1 {
+		t.Error("column found by spec at wrong index:", i)
+	}
+
+	if names := tableColumnNames(table, 2); names[1] != "LATENCY.P50" {
+		t.Error("invalid encoded column name:", names[1])
+	}
+
+	// Names which don't start with groups in brackets are left unchanged.
+	for _, spec := range []string{"IN|OUT", "[ms]", "RATE[ms]", "[open"} {
+		if c := column(spec); len(c.groups()) != 0 || c.name() != spec {
+			t.Errorf("%s: unexpected groups %q and name %q", spec, c.groups(), c.name())
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package cli
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+var (
+	cellStylesMutex sync.RWMutex
+	cellStyles      = map[string]func(interface{}) StyleSet{
+		"negative": styleNegative,
+	}
+)
+
+// RegisterCellStyle associates a style function with name, so it can be used
+// to style the cells of table views created by NewTableView with struct tags
+// like `table:"STATUS,style=status"`. The function receives the values of the
+// struct fields and returns the style applied to the cell.
+//
+// The "negative" style is registered by default, it colors negative numbers
+// in red.
+func RegisterCellStyle(name string, style func(interface{}) StyleSet) {
+	cellStylesMutex.Lock()
+	defer cellStylesMutex.Unlock()
+
+	if style == nil {
+		delete(cellStyles, name)
+	} else {
+		cellStyles[name] = style
+	}
+}
+
+func lookupCellStyle(field reflect.StructField, tag tableTag) (func(interface{}) StyleSet, error) {
+	name, ok := tag.lookup("style")
+
+	if !ok {
+		return nil, nil
+	}
+
+	cellStylesMutex.RLock()
+	style := cellStyles[name]
+	cellStylesMutex.RUnlock()
+
+	if style == nil {
+		return nil, fmt.Errorf("unknown style %q on field %s", name, field.Name)
+	}
+
+	return style, nil
+}
+
+func styleNegative(v interface{}) StyleSet {
+	r := reflect.ValueOf(v)
+
+	switch r.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if r.Int() < 0 {
+			return Red
+		}
+	case reflect.Float32, reflect.Float64:
+		if r.Float() < 0 {
+			return Red
+		}
+	}
+
+	return nil
+}
+
+// CellStyledTableView returns a view of t where cells are styled with the
+// style returned by the given function.
+func CellStyledTableView(t TableView, style func(col int, row int, cell string) StyleSet) TableView {
+	return cellStyledTableView{t, style}
+}
+
+type cellStyledTableView struct {
+	TableView
+	style func(int, int, string) StyleSet
+}
+
+func (t cellStyledTableView) Cell(col int, row int) string {
+	cell := t.TableView.Cell(col, row)
+
+	if style := t.style(col, row, cell); len(style) != 0 && len(cell) != 0 {
+		cell = style.S(cell)
+	}
+
+	return cell
+}
+
+func (t cellStyledTableView) value(col int, row int) (reflect.Value, bool) {
+	return tableCellValue(t.TableView, col, row)
+}
+
+func (t cellStyledTableView) CellSpan(col int, row int) int {
+	return tableCellSpan(t.TableView, col, row)
+}
+
+func (t cellStyledTableView) FooterCell(col int, row int) string {
+	return tableFooterCell(t.TableView, col, row)
+}
+
+func (t cellStyledTableView) FooterSize() int {
+	return tableFooterSize(t.TableView)
+}
+
+func (t cellStyledTableView) aggregate(col int) (Aggregate, bool) {
+	return tableAggregate(t.TableView, col)
+}
+
+func (t cellStyledTableView) format(col int, v reflect.Value) string {
+	return formatTableCell(t.TableView, col, v)
+}
+
+// StripedRowStyle returns a row style function which cycles through styles,
+// for example to render tables with zebra stripes.
+func StripedRowStyle(styles ...StyleSet) func(row int) StyleSet {
+	return func(row int) StyleSet {
+		if len(styles) == 0 {
+			return nil
+		}
+		return styles[row%len(styles)]
+	}
+}
+
+// restyle applies style to s, restoring it after each reset sequence found in
+// s so it spans the whole string.
+func restyle(s []byte, style StyleSet) []byte {
+	seq := style.Bytes()
+	b := make([]byte, 0, len(s)+2*len(seq))
+	b = append(b, seq...)
+
+	ForEachToken(s, func(t Token) {
+		b = append(b, t.Bytes...)
+
+		if t.Kind == CSIToken && t.Final() == 'm' {
+			switch string(t.Params()) {
+			case "", "0":
+				b = append(b, seq...)
+			}
+		}
+	})
+
+	return appendStyleCodes(b, 0)
+}
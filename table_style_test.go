@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestCellStyledTableView(t *testing.T) {
+	table := NewTable("NAME:", ":BALANCE").
+		Append("alice", "42").
+		Append("bob", "-7")
+
+	view := CellStyledTableView(table, func(col int, row int, cell string) StyleSet {
+		if col == 1 && strings.HasPrefix(cell, "-") {
+			return Red
+		}
+		return nil
+	})
+
+	if s := view.Cell(1, 0); s != "42" {
+		t.Errorf("invalid cell: %q", s)
+	}
+
+	if s := view.Cell(1, 1); s != Red.S("-7") {
+		t.Errorf("invalid styled cell: %q", s)
+	}
+
+	buffer := &bytes.Buffer{}
+	RenderTableView(buffer, view)
+
+	const output = "" +
+		"NAME  BALANCE\n" +
+		"alice      42\n" +
+		"bob        \033[31m-7\033[0m\n"
+
+	if s := buffer.String(); s != output {
+		t.Errorf("\n\n%s\n%q", s, s)
+	}
+}
+
+func TestTableViewStyleTag(t *testing.T) {
+	RegisterCellStyle("status", func(v interface{}) StyleSet {
+		switch v.(string) {
+		case "failed":
+			return Red
+		case "warning":
+			return Yellow
+		}
+		return nil
+	})
+	defer RegisterCellStyle("status", nil)
+
+	type T struct {
+		Status  string `table:"STATUS,style=status"`
+		Balance int    `table:"BALANCE,style=negative"`
+	}
+
+	view := NewTableView([]T{{"ok", 1}, {"failed", -1}, {"warning", 0}})
+
+	expected := [][]string{
+		{"ok", "1"},
+		{Red.S("failed"), Red.S("-1")},
+		{Yellow.S("warning"), "0"},
+	}
+
+	for j, row := range expected {
+		for i, cell := range row {
+			if s := view.Cell(i, j); s != cell {
+				t.Errorf("%s (row %d): expected %q but found %q", view.Column(i), j, cell, s)
+			}
+		}
+	}
+}
+
+func TestRenderTableRowStyle(t *testing.T) {
+	table := NewTable("A:", "B:").
+		Append("1", Bold.S("2")).
+		Append("3", "4")
+
+	buffer := &bytes.Buffer{}
+	RenderTableViewWithOptions(buffer, table, TableOptions{
+		RowStyle: StripedRowStyle(nil, Reverse),
+	})
+
+	const output = "" +
+		"A B\n" +
+		"1 \033[1m2\033[0m\n" +
+		"\033[7m3\033[0m\033[7m \033[0m\033[7m4\033[0m\n"
+
+	if s := buffer.String(); s != output {
+		t.Errorf("\n\n%s\n%q", s, s)
+	}
+}
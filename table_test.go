@@ -49,3 +49,275 @@ func TestRenderTable(t *testing.T) {
 		}
 	}
 }
+
+func TestRenderTableWithOptions(t *testing.T) {
+	table := NewTable("ID:", "MESSAGE:", ":SIZE").
+		Append("1", "The quick brown fox jumps over the lazy dog", "42").
+		Append("2", "Hello World!", "1024")
+
+	tests := []struct {
+		name    string
+		options TableOptions
+		output  string
+	}{
+		{
+			name: "Truncate",
+			options: TableOptions{
+				Columns: []ColumnOptions{{}, {MaxWidth: 16}},
+			},
+			output: "" +
+				"ID MESSAGE          SIZE\n" +
+				"1  The quick brown…   42\n" +
+				"2  Hello World!     1024\n",
+		},
+		{
+			name: "Word Wrap",
+			options: TableOptions{
+				Columns: []ColumnOptions{{}, {MaxWidth: 16, Overflow: WordWrapOverflow}},
+			},
+			output: "" +
+				"ID MESSAGE          SIZE\n" +
+				"1  The quick brown    42\n" +
+				"   fox jumps over       \n" +
+				"   the lazy dog         \n" +
+				"2  Hello World!     1024\n",
+		},
+		{
+			name: "Hard Wrap",
+			options: TableOptions{
+				Columns: []ColumnOptions{{}, {MaxWidth: 20, Overflow: WrapOverflow}},
+			},
+			output: "" +
+				"ID MESSAGE              SIZE\n" +
+				"1  The quick brown fox    42\n" +
+				"   jumps over the lazy      \n" +
+				"   dog                      \n" +
+				"2  Hello World!         1024\n",
+		},
+		{
+			name: "Max Width",
+			options: TableOptions{
+				MaxWidth: 20,
+				Columns:  []ColumnOptions{{Fixed: true}, {}, {Fixed: true}},
+			},
+			output: "" +
+				"ID MESSAGE      SIZE\n" +
+				"1  The quick b…   42\n" +
+				"2  Hello World! 1024\n",
+		},
+	}
+
+	buffer := &bytes.Buffer{}
+
+	for _, test := range tests {
+		buffer.Reset()
+
+		if err := RenderTableViewWithOptions(buffer, table, test.options); err != nil {
+			t.Errorf("%s: %s", test.name, err)
+		} else if s := buffer.String(); s != test.output {
+			t.Errorf("%s:\n\n%s\n%q", test.name, s, s)
+		}
+	}
+}
+
+type sizedWriter struct {
+	bytes.Buffer
+	width int
+}
+
+func (w *sizedWriter) Close() error { return nil }
+
+func (w *sizedWriter) Flush() error { return nil }
+
+func (w *sizedWriter) Size() (int, int) { return w.width, 24 }
+
+func TestRenderTableFitToTerminal(t *testing.T) {
+	table := NewTable("ID:", "MESSAGE:", ":SIZE").
+		Append("1", "The quick brown fox jumps over the lazy dog", "42").
+		Append("2", "Hello World!", "1024")
+
+	tests := []struct {
+		name    string
+		width   int
+		options TableOptions
+		output  string
+	}{
+		{
+			name:  "Shrink",
+			width: 24,
+			output: "" +
+				"ID MESSAGE          SIZE\n" +
+				"1  The quick brown…   42\n" +
+				"2  Hello World!     1024\n",
+		},
+		{
+			name:  "Hide Columns",
+			width: 14,
+			options: TableOptions{
+				Columns: []ColumnOptions{{Priority: -1}, {}, {Priority: -2}},
+			},
+			output: "" +
+				"ID MESSAGE    \n" +
+				"1  The quick …\n" +
+				"2  Hello Worl…\n",
+		},
+		{
+			name:  "No Limit",
+			width: 24,
+			options: TableOptions{
+				MaxWidth: -1,
+			},
+			output: "" +
+				"ID MESSAGE                                     SIZE\n" +
+				"1  The quick brown fox jumps over the lazy dog   42\n" +
+				"2  Hello World!                                1024\n",
+		},
+	}
+
+	for _, test := range tests {
+		w := &sizedWriter{width: test.width}
+
+		if err := RenderTableViewWithOptions(w, table, test.options); err != nil {
+			t.Errorf("%s: %s", test.name, err)
+		} else if s := w.String(); s != test.output {
+			t.Errorf("%s:\n\n%s\n%q", test.name, s, s)
+		}
+	}
+}
+
+func TestRenderTableBorders(t *testing.T) {
+	table := NewTable("NAME:", ":SIZE", ":KIND:").
+		Append("hello", "42", "file").
+		Append("world", "1024", "dir")
+
+	tests := []struct {
+		name   string
+		border TableBorder
+		output string
+	}{
+		{
+			name:   "ASCII",
+			border: ASCIIBorder,
+			output: "" +
+				"+-------+------+------+\n" +
+				"| NAME  | SIZE | KIND |\n" +
+				"+-------+------+------+\n" +
+				"| hello |   42 | file |\n" +
+				"| world | 1024 | dir  |\n" +
+				"+-------+------+------+\n",
+		},
+		{
+			name:   "Rounded",
+			border: RoundedBorder,
+			output: "" +
+				"╭───────┬──────┬──────╮\n" +
+				"│ NAME  │ SIZE │ KIND │\n" +
+				"├───────┼──────┼──────┤\n" +
+				"│ hello │   42 │ file │\n" +
+				"│ world │ 1024 │ dir  │\n" +
+				"╰───────┴──────┴──────╯\n",
+		},
+		{
+			name:   "Markdown",
+			border: MarkdownBorder,
+			output: "" +
+				"| NAME  | SIZE | KIND |\n" +
+				"|:------|-----:|:----:|\n" +
+				"| hello |   42 | file |\n" +
+				"| world | 1024 | dir  |\n",
+		},
+		{
+			name:   "Header Rule",
+			border: TableBorder{Separator: "  ", Header: TableBorderLine{Fill: "─", Separator: "  "}},
+			output: "" +
+				"NAME   SIZE  KIND\n" +
+				"─────  ────  ────\n" +
+				"hello    42  file\n" +
+				"world  1024  dir \n",
+		},
+	}
+
+	buffer := &bytes.Buffer{}
+
+	for _, test := range tests {
+		buffer.Reset()
+
+		if err := RenderTableViewWithOptions(buffer, table, TableOptions{Border: &test.border}); err != nil {
+			t.Errorf("%s: %s", test.name, err)
+		} else if s := buffer.String(); s != test.output {
+			t.Errorf("%s:\n\n%s\n%q", test.name, s, s)
+		}
+	}
+
+	border := LightBorder
+	border.Style = Style(Dim, Blue)
+	buffer.Reset()
+
+	if err := RenderTableViewWithOptions(buffer, StyledTableView(table, Bold), TableOptions{Border: &border}); err != nil {
+		t.Fatal(err)
+	}
+
+	b := func(s string) string { return "\x1b[2;34m" + s + "\x1b[0m" }
+	h := func(s string) string { return "\x1b[1m" + s + "\x1b[0m" }
+
+	output := "" +
+		b("┌───────┬──────┬──────┐") + "\n" +
+		b("│") + " " + h("NAME") + "  " + b("│") + " " + h("SIZE") + " " + b("│") + " " + h("KIND") + " " + b("│") + "\n" +
+		b("├───────┼──────┼──────┤") + "\n" +
+		b("│") + " hello " + b("│") + "   42 " + b("│") + " file " + b("│") + "\n" +
+		b("│") + " world " + b("│") + " 1024 " + b("│") + " dir  " + b("│") + "\n" +
+		b("└───────┴──────┴──────┘") + "\n"
+
+	if s := buffer.String(); s != output {
+		t.Errorf("Style:\n\n%s\n%q", s, s)
+	}
+}
+
+func TestRenderTableMarkdownEscape(t *testing.T) {
+	table := NewTable("A|B", "C").
+		Append("a|b", "c").
+		Append(`x\y`, "|")
+
+	buffer := &bytes.Buffer{}
+
+	if err := RenderTableViewWithOptions(buffer, table, TableOptions{Border: &MarkdownBorder}); err != nil {
+		t.Fatal(err)
+	}
+
+	const output = "" +
+		"| A\\|B | C  |\n" +
+		"|:----:|:--:|\n" +
+		"| a\\|b | c  |\n" +
+		"| x\\y  | \\| |\n"
+
+	if s := buffer.String(); s != output {
+		t.Errorf("\n\n%s\n%q", s, s)
+	}
+}
+
+func TestRenderTableMultiLineCells(t *testing.T) {
+	table := NewTable("TOP:", "MIDDLE:", "BOTTOM:", "LINES:").
+		Append("a", "b", "c", "1\n\033[1m2\n3\033[0m\n4").
+		Append("d", "e", "f", "5")
+
+	buffer := &bytes.Buffer{}
+	RenderTableViewWithOptions(buffer, table, TableOptions{
+		Columns: []ColumnOptions{
+			{VerticalAlign: TopAlign},
+			{VerticalAlign: MiddleAlign},
+			{VerticalAlign: BottomAlign},
+		},
+	})
+
+	const output = "" +
+		"TOP MIDDLE BOTTOM LINES\n" +
+		"a                 1    \n" +
+		"    b             \033[1m2\033[0m    \n" +
+		"                  \033[1m3\033[0m    \n" +
+		"           c      4    \n" +
+		"d   e      f      5    \n"
+
+	if s := buffer.String(); s != output {
+		t.Errorf("\n\n%s\n%q", s, s)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"io"
 	"reflect"
+	"sort"
+	"strings"
 )
 
 type TableView interface {
@@ -14,168 +16,977 @@ type TableView interface {
 	Size() (cols int, rows int)
 }
 
+// Overflow defines how cells wider than their column are rendered.
+type Overflow int
+
+const (
+	// TruncateOverflow cuts cells to the width of their column and ends them
+	// with an ellipsis.
+	TruncateOverflow Overflow = iota
+
+	// WrapOverflow breaks cells in multiple lines at the column width.
+	WrapOverflow
+
+	// WordWrapOverflow breaks cells in multiple lines between words.
+	WordWrapOverflow
+)
+
+// VerticalAlign defines where cells are placed in rows taller than they are.
+type VerticalAlign int
+
+const (
+	TopAlign VerticalAlign = iota
+	MiddleAlign
+	BottomAlign
+)
+
+// ColumnOptions configures how a single table column is rendered.
+type ColumnOptions struct {
+	// MinWidth is the minimum width of the column. When zero, the column is
+	// never shrunk below the width of its name.
+	MinWidth int
+
+	// MaxWidth is the maximum width of the column, zero means no limit.
+	MaxWidth int
+
+	// Overflow defines how cells wider than the column are rendered.
+	Overflow Overflow
+
+	// VerticalAlign defines how cells are aligned in rows where other cells
+	// span multiple lines.
+	VerticalAlign VerticalAlign
+
+	// Fixed columns are not shrunk to fit tables in their maximum width.
+	Fixed bool
+
+	// Priority ranks the columns that get hidden when a table doesn't fit in
+	// its maximum width after shrinking its flexible columns. Only columns
+	// with a negative priority are hidden, lowest priority first.
+	Priority int
+}
+
+// TableOptions configures the rendering of tables.
+type TableOptions struct {
+	// Columns holds the options of each column of the table, indexed by column
+	// position. Columns with no options use the zero-value ColumnOptions.
+	Columns []ColumnOptions
+
+	// Border defines the characters drawn around and between the cells of the
+	// table, nil means NoBorder.
+	Border *TableBorder
+
+	// RowStyle returns the style applied to a row, including the padding of
+	// its cells. The row index is the position of the row in the table.
+	RowStyle func(row int) StyleSet
+
+	// MaxWidth is the maximum width of the table, flexible columns are shrunk
+	// until the table fits. Zero means the width of the terminal when writing
+	// to a terminal Writer, a negative value means no limit.
+	MaxWidth int
+}
+
+func (options *TableOptions) column(col int) (c ColumnOptions) {
+	if col < len(options.Columns) {
+		c = options.Columns[col]
+	}
+	return
+}
+
+func (options *TableOptions) rowStyle(row int) StyleSet {
+	if options.RowStyle == nil {
+		return nil
+	}
+	return options.RowStyle(row)
+}
+
+func (options *TableOptions) border() *TableBorder {
+	if options.Border == nil {
+		return &NoBorder
+	}
+	return options.Border
+}
+
 func RenderTableView(w io.Writer, t TableView) (err error) {
+	return RenderTableViewWithOptions(w, t, TableOptions{})
+}
+
+// RenderTableViewWithOptions is like RenderTableView but allows the program
+// to configure how the table is laid out.
+//
+// When options.MaxWidth is zero and w is a Writer connected to a terminal, the
+// table is fit to the width of the terminal.
+func RenderTableViewWithOptions(w io.Writer, t TableView, options TableOptions) (err error) {
 	cols, rows := t.Size()
-	return renderTableView(w, t, computeTableColumnWidths(t, cols, rows), cols, rows)
+	options.MaxWidth = fitWidth(w, options.MaxWidth)
+	options.Border = tableBorder(w, options.Border)
+	t = escapedTableView(t, options.Border)
+	return renderTableView(w, t, &options, layoutTableView(t, &options, cols, rows), rows)
 }
 
-func renderTableView(w io.Writer, t TableView, widths []int, cols int, rows int) (err error) {
-	if err = renderTableViewColumns(w, t, widths, cols, rows); err == nil {
-		err = renderTableViewRows(w, t, widths, cols, rows)
+func renderTableView(w io.Writer, t TableView, options *TableOptions, layout *tableLayout, rows int) (err error) {
+	if err = renderTableViewColumns(w, t, options, layout); err == nil {
+		err = renderTableViewRows(w, t, options, layout, rows)
 	}
 	return
 }
 
 func RenderTableViewColumns(w io.Writer, t TableView) (err error) {
 	cols, rows := t.Size()
-	return renderTableViewColumns(w, t, computeTableColumnWidths(t, cols, rows), cols, rows)
+	options := &TableOptions{MaxWidth: fitWidth(w, 0)}
+	return renderTableViewColumns(w, t, options, layoutTableView(t, options, cols, rows))
 }
 
-func renderTableViewColumns(w io.Writer, t TableView, widths []int, cols int, rows int) (err error) {
-	for i := 0; i != cols; i++ {
-		if i != 0 {
-			if _, err = io.WriteString(w, " "); err != nil {
-				return
-			}
-		}
+func renderTableViewColumns(w io.Writer, t TableView, options *TableOptions, layout *tableLayout) (err error) {
+	border := options.border()
+
+	groups, depth := tableColumnGroups(t, len(layout.widths))
+	span := func(i int) int { return groupSpan(groups, 0, i) }
+
+	if depth == 0 {
+		span = nil
+	}
+
+	if err = border.renderSpannedLine(w, border.Top, layout, span); err != nil {
+		return
+	}
+
+	if err = renderTableViewHeader(w, t, options, layout); err != nil {
+		return
+	}
+
+	return border.renderLine(w, border.Header, layout)
+}
+
+func renderTableViewHeader(w io.Writer, t TableView, options *TableOptions, layout *tableLayout) (err error) {
+	border := options.border()
+	groups, depth := tableColumnGroups(t, len(layout.widths))
 
-		if err = RenderColumn(w, t.Column(i), widths[i]); err != nil {
+	// Groups are rendered above the column names, one row per level, each
+	// group centered over the columns that belong to it.
+	for l := 0; l != depth; l++ {
+		level := l
+
+		if err = border.renderRow(w, layout, tableRow{
+			cell: func(i int, width int) string {
+				if level < len(groups[i]) {
+					return Truncate(groups[i][level], width, Ellipsis)
+				}
+				return ""
+			},
+			span:  func(i int) int { return groupSpan(groups, level, i) },
+			align: func(int) CellAlign { return Centered },
+		}); err != nil {
 			return
 		}
 	}
 
-	_, err = io.WriteString(w, "\n")
-	return
+	return border.renderRow(w, layout, tableRow{
+		cell: func(i int, width int) string {
+			return Truncate(column(t.Column(i)).name(), width, Ellipsis)
+		},
+	})
 }
 
 func RenderTableViewRows(w io.Writer, t TableView) (err error) {
 	cols, rows := t.Size()
-	return renderTableViewRows(w, t, computeTableColumnWidths(t, cols, rows), cols, rows)
+	options := &TableOptions{MaxWidth: fitWidth(w, 0), Border: tableBorder(w, nil)}
+	return renderTableViewRows(w, t, options, layoutTableView(t, options, cols, rows), rows)
 }
 
-func renderTableViewRows(w io.Writer, t TableView, widths []int, cols int, rows int) (err error) {
+func renderTableViewRows(w io.Writer, t TableView, options *TableOptions, layout *tableLayout, rows int) (err error) {
+	lines := make([][]string, len(layout.widths))
+
 	for j := 0; j != rows; j++ {
-		for i := 0; i != cols; i++ {
-			if i != 0 {
-				if _, err = io.WriteString(w, " "); err != nil {
-					return
+		if err = renderTableViewRow(w, t, options, layout, lines, j, options.rowStyle(j)); err != nil {
+			return
+		}
+	}
+
+	border := options.border()
+
+	if err = renderTableViewFooter(w, t, options, layout, lines); err != nil {
+		return
+	}
+
+	// The bottom line has no junctions under the merged cells of the last
+	// row, footers have no merged cells.
+	var span func(int) int
+
+	if last := rows - 1; last >= 0 && tableFooterSize(t) == 0 {
+		span = func(i int) int { return tableCellSpan(t, i, last) }
+	}
+
+	return border.renderSpannedLine(w, border.Bottom, layout, span)
+}
+
+func renderTableViewFooter(w io.Writer, t TableView, options *TableOptions, layout *tableLayout, lines [][]string) (err error) {
+	f, ok := t.(FooterTableView)
+
+	if !ok || f.FooterSize() == 0 {
+		return
+	}
+
+	border := options.border()
+
+	if err = border.renderLine(w, border.Footer, layout); err != nil {
+		return
+	}
+
+	footer := footerTableView{f}
+
+	for j, n := 0, f.FooterSize(); j != n; j++ {
+		if err = renderTableViewRow(w, footer, options, layout, lines, j, nil); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func renderTableViewRow(w io.Writer, t TableView, options *TableOptions, layout *tableLayout, lines [][]string, row int, style StyleSet) (err error) {
+	border := options.border()
+	height := 1
+	span := func(i int) int { return tableCellSpan(t, i, row) }
+
+	for n := 0; n < len(layout.columns); {
+		i := layout.columns[n]
+		width, next := border.spanWidth(layout, n, span(i))
+		lines[i] = layoutCell(t.Cell(i, row), width, options.column(i).Overflow)
+
+		if n := len(lines[i]); n > height {
+			height = n
+		}
+
+		n = next
+	}
+
+	for l := 0; l != height; l++ {
+		if err = border.renderRow(w, layout, tableRow{
+			style: style,
+			span:  span,
+			cell: func(i int, width int) string {
+				n := len(lines[i])
+
+				switch options.column(i).VerticalAlign {
+				case MiddleAlign:
+					n = l - (height-n)/2
+				case BottomAlign:
+					n = l - (height - n)
+				default:
+					n = l
 				}
-			}
 
-			if err = RenderCell(w, t.Cell(i, j), widths[i], column(t.Column(i)).alignment()); err != nil {
-				return
+				if n >= 0 && n < len(lines[i]) {
+					return lines[i][n]
+				}
+				return ""
+			},
+		}); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func layoutCell(cell string, width int, overflow Overflow) []string {
+	multiline := strings.IndexByte(cell, '\n') >= 0
+
+	if cellWidth(cell) <= width {
+		if multiline {
+			return Wrap(cell, 0)
+		}
+		return []string{cell}
+	}
+
+	switch overflow {
+	case WrapOverflow:
+		return Wrap(cell, width)
+	case WordWrapOverflow:
+		return WordWrap(cell, width)
+	}
+
+	if !multiline {
+		return []string{Truncate(cell, width, Ellipsis)}
+	}
+
+	lines := Wrap(cell, 0)
+
+	for i, line := range lines {
+		lines[i] = Truncate(line, width, Ellipsis)
+	}
+
+	return lines
+}
+
+// cellWidth returns the display width of the longest line of cell.
+func cellWidth(cell string) (width int) {
+	for {
+		i := strings.IndexByte(cell, '\n')
+
+		if i < 0 {
+			break
+		}
+
+		if w := DisplayWidthInString(cell[:i]); w > width {
+			width = w
+		}
+
+		cell = cell[i+1:]
+	}
+
+	if w := DisplayWidthInString(cell); w > width {
+		width = w
+	}
+
+	return
+}
+
+// tableLayout holds the positions and sizes of the columns of a table.
+type tableLayout struct {
+	columns []int // indexes of the visible columns
+	widths  []int // widths of all columns, indexed by column
+	aligns  []CellAlign
+}
+
+func layoutTableView(t TableView, options *TableOptions, cols int, rows int) *tableLayout {
+	layout := &tableLayout{
+		columns: make([]int, cols),
+		widths:  computeTableColumnWidths(t, options, cols, rows),
+		aligns:  make([]CellAlign, cols),
+	}
+
+	for i := 0; i != cols; i++ {
+		layout.columns[i] = i
+		layout.aligns[i] = column(t.Column(i)).alignment()
+	}
+
+	if options.MaxWidth > 0 {
+		fitTableLayout(layout, t, options)
+	}
+
+	return layout
+}
+
+// fitTableLayout shrinks the flexible columns of the layout so the table fits
+// in the maximum width, hiding columns with a negative priority if shrinking
+// is not enough.
+func fitTableLayout(layout *tableLayout, t TableView, options *TableOptions) {
+	widths := make([]int, len(layout.widths))
+	copy(widths, layout.widths)
+
+	for {
+		copy(layout.widths, widths)
+
+		if shrinkTableColumnWidths(layout, t, options) {
+			return
+		}
+
+		hide := -1
+
+		for n, i := range layout.columns {
+			p := options.column(i).Priority
+
+			if p < 0 && (hide < 0 || p <= options.column(layout.columns[hide]).Priority) {
+				hide = n
 			}
 		}
 
-		if _, err = io.WriteString(w, "\n"); err != nil {
+		if hide < 0 {
 			return
 		}
+
+		layout.columns = append(layout.columns[:hide], layout.columns[hide+1:]...)
 	}
-	return
 }
 
-func computeTableColumnWidths(t TableView, cols int, rows int) (widths []int) {
+// shrinkTableColumnWidths reduces the widths of flexible columns, starting with
+// the widest ones, until the table fits in its maximum width or the columns all
+// reached their minimum width. The function returns whether the table fits.
+func shrinkTableColumnWidths(layout *tableLayout, t TableView, options *TableOptions) bool {
+	mins := make([]int, len(layout.widths))
+	total := options.border().width(len(layout.columns))
+
+	for _, i := range layout.columns {
+		c := options.column(i)
+		w := layout.widths[i]
+
+		switch {
+		case c.Fixed:
+			mins[i] = w
+		case c.MinWidth > 0:
+			mins[i] = c.MinWidth
+		default:
+			mins[i] = DisplayWidthInString(column(t.Column(i)).name())
+		}
+
+		if mins[i] < 1 {
+			mins[i] = 1
+		}
+
+		total += w
+	}
+
+	for total > options.MaxWidth {
+		widest := -1
+
+		for _, i := range layout.columns {
+			if w := layout.widths[i]; w > mins[i] && (widest < 0 || w > layout.widths[widest]) {
+				widest = i
+			}
+		}
+
+		if widest < 0 {
+			return false
+		}
+
+		layout.widths[widest]--
+		total--
+	}
+
+	return true
+}
+
+func computeTableColumnWidths(t TableView, options *TableOptions, cols int, rows int) (widths []int) {
 	widths = make([]int, cols)
+	border := options.border()
 
 	for i := 0; i != cols; i++ {
-		widths[i] = RuneCountInString(column(t.Column(i)).string())
+		widths[i] = DisplayWidthInString(column(t.Column(i)).name())
+	}
+
+	for j := 0; j != rows; j++ {
+		for i := 0; i != cols; i++ {
+			// Cells spanning multiple columns are measured once the widths
+			// of the columns they cover are known.
+			if tableCellSpan(t, i, j) == 1 {
+				if w := cellWidth(t.Cell(i, j)); w > widths[i] {
+					widths[i] = w
+				}
+			}
+		}
+	}
+
+	if f, ok := t.(FooterTableView); ok {
+		for j, n := 0, f.FooterSize(); j != n; j++ {
+			for i := 0; i != cols; i++ {
+				if w := cellWidth(f.FooterCell(i, j)); w > widths[i] {
+					widths[i] = w
+				}
+			}
+		}
 	}
 
 	for j := 0; j != rows; j++ {
 		for i := 0; i != cols; i++ {
-			if w := RuneCountInString(t.Cell(i, j)); w > widths[i] {
-				widths[i] = w
+			if n := tableCellSpan(t, i, j); n > 1 {
+				widenTableColumns(widths, border, i, n, cellWidth(t.Cell(i, j)))
 			}
 		}
 	}
 
+	groups, depth := tableColumnGroups(t, cols)
+
+	for l := 0; l != depth; l++ {
+		for i := 0; i < cols; {
+			n := groupSpan(groups, l, i)
+
+			if l < len(groups[i]) {
+				widenTableColumns(widths, border, i, n, DisplayWidthInString(groups[i][l]))
+			}
+
+			i += n
+		}
+	}
+
+	for i := 0; i != cols; i++ {
+		c := options.column(i)
+
+		if c.MaxWidth > 0 && widths[i] > c.MaxWidth {
+			widths[i] = c.MaxWidth
+		}
+
+		if widths[i] < c.MinWidth {
+			widths[i] = c.MinWidth
+		}
+	}
+
 	return
 }
 
+// NewTableView returns a table view of v, which may be a TableView, a slice or
+// array of structs, pointers to structs, maps with string keys or scalars, a
+// map with string keys, or a channel or iterator function of such values.
+// Channels are drained until they are closed, the function blocks until then.
+//
+// The function panics if v cannot be represented as a table, TableViewOf can
+// be used instead to get an error.
 func NewTableView(v interface{}) TableView {
+	view, err := newTableView(v)
+	if err != nil {
+		panic("cli.NewTableView: " + err.Error())
+	}
+	return view
+}
+
+// TableViewOf is like NewTableView but returns an error instead of panicking
+// when v cannot be represented as a table.
+func TableViewOf(v interface{}) (TableView, error) {
+	view, err := newTableView(v)
+	if err != nil {
+		return nil, fmt.Errorf("cli.TableViewOf: %s", err)
+	}
+	return view, nil
+}
+
+func newTableView(v interface{}) (TableView, error) {
 	if view, ok := v.(TableView); ok {
-		return view
+		return view, nil
 	}
-	return newTableView(reflect.ValueOf(v))
+	return newReflectTableView(reflect.ValueOf(v))
 }
 
-func newTableView(v reflect.Value) TableView {
+func newReflectTableView(v reflect.Value) (view reflectTableView, err error) {
+	if !v.IsValid() {
+		err = fmt.Errorf("unsupported nil value")
+		return
+	}
+
+	view.cache = &footerCache{}
+
 	t := v.Type()
 
 	switch t.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			return newReflectTableView(v.Elem())
+		}
+
 	case reflect.Array, reflect.Slice:
-		switch t.Elem().Kind() {
-		case reflect.Struct:
-			return makeStructSliceTableView(v, t)
+		view.n = v.Len()
+		err = view.appendColumns(t.Elem(), v.Index)
+		return
+
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			break
+		}
+
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i int, j int) bool { return keys[i].String() < keys[j].String() })
+
+		view.n = len(keys)
+		view.c = append(view.c, "KEY")
+		view.f = append(view.f, func(row int) reflect.Value { return keys[row] })
+		view.x = append(view.x, makeDefaultCellFormat())
+		view.s = append(view.s, nil)
+		view.a = append(view.a, 0)
+		err = view.appendColumns(t.Elem(), func(row int) reflect.Value { return v.MapIndex(keys[row]) })
+		return
+
+	case reflect.Chan:
+		if (t.ChanDir() & reflect.RecvDir) == 0 {
+			break
+		}
+
+		if v.IsNil() {
+			// Receiving from a nil channel would block forever.
+			err = fmt.Errorf("cannot read the rows of a nil %s", t)
+			return
+		}
+
+		s := reflect.MakeSlice(reflect.SliceOf(t.Elem()), 0, v.Len())
+
+		for {
+			x, ok := v.Recv()
+			if !ok {
+				break
+			}
+			s = reflect.Append(s, x)
+		}
+
+		return newReflectTableView(s)
+
+	case reflect.Func:
+		// Iterator functions have the func(yield func(T) bool) signature of
+		// iter.Seq, values are collected before building the view.
+		if t.NumIn() != 1 || t.NumOut() != 0 || v.IsNil() {
+			break
+		}
+
+		yield := t.In(0)
+
+		if yield.Kind() != reflect.Func || yield.NumIn() != 1 || yield.NumOut() != 1 || yield.Out(0).Kind() != reflect.Bool {
+			break
 		}
+
+		s := reflect.MakeSlice(reflect.SliceOf(yield.In(0)), 0, 0)
+		v.Call([]reflect.Value{reflect.MakeFunc(yield, func(args []reflect.Value) []reflect.Value {
+			s = reflect.Append(s, args[0])
+			return []reflect.Value{reflect.ValueOf(true)}
+		})})
+
+		return newReflectTableView(s)
 	}
 
-	panic(fmt.Sprintf("cli.NewTableView: unsupported value, expectes slice of struct or cli.TableView but got %T", v.Interface()))
+	err = fmt.Errorf("unsupported value, expected a slice, map, channel or iterator of struct, map or scalar values but got %s", t)
+	return
 }
 
-type structSliceTableView struct {
-	v reflect.Value
-	t reflect.Type
+// reflectTableView is the table view of Go values created by NewTableView. Each
+// column reads the values of its cells with a function of the row index.
+type reflectTableView struct {
+	n int
 	c []string
-	f []func(reflect.Value) reflect.Value
+	f []func(int) reflect.Value
+	x []cellFormat
+	s []func(interface{}) StyleSet
+	a []Aggregate
+
+	// cache holds the aggregates of the footer once computed.
+	cache *footerCache
 }
 
-func makeStructSliceTableView(v reflect.Value, t reflect.Type) structSliceTableView {
-	e := t.Elem()
-	s := structSliceTableView{
-		v: v,
-		t: t,
-		c: make([]string, 0, e.NumField()),
+// appendColumns adds the columns of rows of type t to the view, the values of
+// the rows are returned by get. Structs have one column per field, maps with
+// string keys have one column per key found in any of the rows, other types are
+// rendered in a single VALUE column.
+func (s *reflectTableView) appendColumns(t reflect.Type, get func(int) reflect.Value) (err error) {
+	e := t
+
+	for e.Kind() == reflect.Ptr {
+		e = e.Elem()
 	}
 
-	forEachColumn(e, func(col string, get func(reflect.Value) reflect.Value) {
-		s.c = append(s.c, col)
+	switch {
+	case e.Kind() == reflect.Struct:
+		ferr := forEachColumn(e, func(field reflect.StructField, tag tableTag, g func(reflect.Value) reflect.Value) {
+			if err != nil {
+				return
+			}
+
+			var f cellFormat
+			var style func(interface{}) StyleSet
+			var agg Aggregate
+
+			if f, err = makeCellFormat(field, tag); err != nil {
+				return
+			}
+
+			if style, err = lookupCellStyle(field, tag); err != nil {
+				return
+			}
+
+			if agg, err = lookupAggregate(field, tag); err != nil {
+				return
+			}
+
+			s.c = append(s.c, tag.name)
+			s.f = append(s.f, func(row int) reflect.Value {
+				if v := indirectValue(get(row)); v.IsValid() {
+					return g(v)
+				}
+				return reflect.Value{}
+			})
+			s.x = append(s.x, f)
+			s.s = append(s.s, style)
+			s.a = append(s.a, agg)
+		})
+
+		if err == nil {
+			err = ferr
+		}
+
+	case e.Kind() == reflect.Map && e.Key().Kind() == reflect.String:
+		keys := make(map[string]bool)
+
+		for j := 0; j != s.n; j++ {
+			if v := indirectValue(get(j)); v.IsValid() {
+				for _, k := range v.MapKeys() {
+					keys[k.String()] = true
+				}
+			}
+		}
+
+		names := make([]string, 0, len(keys))
+
+		for k := range keys {
+			names = append(names, k)
+		}
+
+		sort.Strings(names)
+
+		for _, name := range names {
+			key := reflect.ValueOf(name).Convert(e.Key())
+			s.c = append(s.c, name)
+			s.f = append(s.f, func(row int) reflect.Value {
+				if v := indirectValue(get(row)); v.IsValid() {
+					return v.MapIndex(key)
+				}
+				return reflect.Value{}
+			})
+			s.x = append(s.x, makeDefaultCellFormat())
+			s.s = append(s.s, nil)
+			s.a = append(s.a, 0)
+		}
+
+	default:
+		s.c = append(s.c, "VALUE")
 		s.f = append(s.f, get)
-	})
+		s.x = append(s.x, makeDefaultCellFormat())
+		s.s = append(s.s, nil)
+		s.a = append(s.a, 0)
+	}
 
-	return s
+	return
 }
 
-func (s structSliceTableView) Column(col int) string {
+func indirectValue(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+func (s reflectTableView) Column(col int) string {
 	return s.c[col]
 }
 
-func (s structSliceTableView) Cell(col int, row int) (cell string) {
-	cell = fmt.Sprint(s.f[col](s.v.Index(row)).Interface())
+func (s reflectTableView) Cell(col int, row int) (cell string) {
+	v := s.f[col](row)
+	cell = s.x[col].format(v)
+
+	if style := s.s[col]; style != nil && len(cell) != 0 && v.IsValid() {
+		if set := style(v.Interface()); len(set) != 0 {
+			cell = set.S(cell)
+		}
+	}
+
 	return
 }
 
-func (s structSliceTableView) Size() (cols int, rows int) {
-	cols, rows = len(s.c), s.v.Len()
+func (s reflectTableView) FooterCell(col int, row int) string {
+	if agg := s.a[col]; agg != 0 {
+		return s.cache.get(col, func() string { return aggregateColumn(s, col, agg) })
+	}
+	return ""
+}
+
+func (s reflectTableView) aggregate(col int) (Aggregate, bool) {
+	return s.a[col], true
+}
+
+func (s reflectTableView) FooterSize() int {
+	for _, agg := range s.a {
+		if agg != 0 {
+			return 1
+		}
+	}
+	return 0
+}
+
+func (s reflectTableView) format(col int, v reflect.Value) string {
+	return s.x[col].format(v)
+}
+
+func (s reflectTableView) value(col int, row int) (reflect.Value, bool) {
+	v := s.f[col](row)
+	return v, v.IsValid()
+}
+
+func (s reflectTableView) Size() (cols int, rows int) {
+	cols, rows = len(s.c), s.n
 	return
 }
 
-func forEachColumn(t reflect.Type, do func(string, func(reflect.Value) reflect.Value)) {
-	for i, n := 0, t.NumField(); i != n; i++ {
+// forEachColumn calls do for each column of struct type t, with the field that
+// holds the values of the column, its tag, and a function returning the value
+// of the field in a struct of type t.
+//
+// Anonymous struct fields are flattened, named struct fields are flattened as
+// well when their tag has the inline or prefix options. The field option reads
+// a nested field with a dotted path (e.g. `table:"CITY,field=Address.City"`),
+// the fields option selects the nested fields of inlined structs (e.g.
+// `table:",inline,fields=Name Address.City"`). Nil pointers met along the way
+// produce invalid values, which are rendered as empty cells.
+func forEachColumn(t reflect.Type, do func(reflect.StructField, tableTag, func(reflect.Value) reflect.Value)) (err error) {
+	return forEachColumnOf(t, nil, do)
+}
+
+// forEachColumnOf is the implementation of forEachColumn, parents holds the
+// types being flattened, which would never end if t was one of them.
+func forEachColumnOf(t reflect.Type, parents []reflect.Type, do func(reflect.StructField, tableTag, func(reflect.Value) reflect.Value)) (err error) {
+	t = indirectType(t)
+
+	for _, p := range parents {
+		if p == t {
+			return fmt.Errorf("cannot flatten the fields of %s, the type is recursive", t)
+		}
+	}
+
+	parents = append(parents[:len(parents):len(parents)], t)
+
+	for i, n := 0, t.NumField(); i != n && err == nil; i++ {
 		f := t.Field(i)
 		g := func(v reflect.Value) reflect.Value {
-			return v.FieldByIndex(f.Index)
+			return fieldByIndex(v, f.Index)
 		}
+		tag := parseTableTag(f.Tag.Get("table"))
+		_, inline := tag.lookup("inline")
+		prefix, ok := tag.lookup("prefix")
+		inline = inline || ok
 
-		if f.Anonymous {
+		if tag.name == "-" {
+			continue
+		}
+
+		if f.Anonymous && !inline && indirectType(f.Type).Kind() == reflect.Struct {
 			// We want to flatten out the anonymous fields so they appear in the
 			// table as top-level columns.
 			// We call recursively and decorate the callback to do the recusive
 			// field lookup among multiple levels if necessary.
-			forEachColumn(f.Type, func(col string, get func(reflect.Value) reflect.Value) {
-				do(col, func(v reflect.Value) reflect.Value { return get(g(v)) })
+			err = forEachColumnOf(f.Type, parents, func(field reflect.StructField, tag tableTag, get func(reflect.Value) reflect.Value) {
+				do(field, tag, func(v reflect.Value) reflect.Value { return get(g(v)) })
 			})
 			continue
 		}
 
-		switch name := f.Tag.Get("table"); name {
-		case "-":
-		case "":
-			do(f.Name, g)
-		default:
-			do(name, g)
+		if len(f.PkgPath) != 0 {
+			// Unexported fields cannot be read through reflection.
+			continue
+		}
+
+		if path, ok := tag.lookup("field"); ok {
+			var leaf reflect.StructField
+			var index []int
+
+			if leaf, index, err = fieldPath(f, path); err != nil {
+				return
+			}
+
+			if len(tag.name) == 0 {
+				tag.name = f.Name + "." + path
+			}
+
+			do(leaf, tag, func(v reflect.Value) reflect.Value { return fieldByIndex(g(v), index) })
+			continue
+		}
+
+		if !inline {
+			if len(tag.name) == 0 {
+				tag.name = f.Name
+			}
+			do(f, tag, g)
+			continue
+		}
+
+		if indirectType(f.Type).Kind() != reflect.Struct {
+			return fmt.Errorf("cannot inline field %s of type %s, expected a struct", f.Name, f.Type)
+		}
+
+		// The name of inlined fields, if any, groups their columns under a
+		// common header.
+		rename := func(name string) string {
+			name = prefixColumn(name, prefix)
+
+			if len(tag.name) != 0 {
+				name = prefixColumn(name, "["+column(tag.name).string()+"]")
+			}
+
+			return name
+		}
+
+		if fields, ok := tag.lookup("fields"); ok {
+			for _, path := range strings.Fields(fields) {
+				var leaf reflect.StructField
+				var index []int
+
+				if leaf, index, err = fieldPath(f, path); err != nil {
+					return
+				}
+
+				sub := parseTableTag(leaf.Tag.Get("table"))
+
+				if len(sub.name) == 0 || sub.name == "-" || strings.IndexByte(path, '.') >= 0 {
+					sub.name = path
+				}
+
+				sub.name = rename(sub.name)
+				do(leaf, sub, func(v reflect.Value) reflect.Value { return fieldByIndex(g(v), index) })
+			}
+			continue
 		}
+
+		err = forEachColumnOf(f.Type, parents, func(field reflect.StructField, sub tableTag, get func(reflect.Value) reflect.Value) {
+			sub.name = rename(sub.name)
+			do(field, sub, func(v reflect.Value) reflect.Value { return get(g(v)) })
+		})
 	}
+
+	return
+}
+
+// fieldPath resolves the dotted path of Go field names starting at field f,
+// returning the field at the end of the path and its index in the type of f.
+func fieldPath(f reflect.StructField, path string) (leaf reflect.StructField, index []int, err error) {
+	t := f.Type
+
+	for _, name := range strings.Split(path, ".") {
+		var ok bool
+
+		if t = indirectType(t); t.Kind() == reflect.Struct {
+			leaf, ok = t.FieldByName(name)
+		}
+
+		if !ok || len(leaf.PkgPath) != 0 {
+			err = fmt.Errorf("invalid path %q on field %s, %s has no exported field named %s", path, f.Name, t, name)
+			return
+		}
+
+		index = append(index, leaf.Index...)
+		t = leaf.Type
+	}
+
+	return
+}
+
+// fieldByIndex is like reflect.Value.FieldByIndex but it dereferences pointers
+// before each step and returns an invalid value if one of them is nil.
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for _, i := range index {
+		for v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}
+			}
+			v = v.Elem()
+		}
+
+		if !v.IsValid() {
+			return v
+		}
+
+		v = v.Field(i)
+	}
+	return v
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// prefixColumn adds prefix to the name of the column spec, keeping alignment
+// markers at the start of the spec.
+func prefixColumn(spec string, prefix string) string {
+	if len(spec) != 0 && spec[0] == ':' {
+		return ":" + prefix + spec[1:]
+	}
+	return prefix + spec
 }
 
 func StyledTableView(t TableView, style StyleSet) TableView {
@@ -200,5 +1011,37 @@ func (t styledTableView) Column(col int) string {
 		s, r = s[:n-1], s[n-1:]
 	}
 
-	return l + t.style.S(s) + r
+	// Groups and names are styled separately so each part of grouped column
+	// specs is rendered with balanced styles.
+	groups, name := column(s).split()
+
+	for _, g := range groups {
+		l += "[" + t.style.S(g) + "]"
+	}
+
+	return l + t.style.S(name) + r
+}
+
+func (t styledTableView) CellSpan(col int, row int) int {
+	return tableCellSpan(t.TableView, col, row)
+}
+
+func (t styledTableView) FooterCell(col int, row int) string {
+	return tableFooterCell(t.TableView, col, row)
+}
+
+func (t styledTableView) FooterSize() int {
+	return tableFooterSize(t.TableView)
+}
+
+func (t styledTableView) aggregate(col int) (Aggregate, bool) {
+	return tableAggregate(t.TableView, col)
+}
+
+func (t styledTableView) format(col int, v reflect.Value) string {
+	return formatTableCell(t.TableView, col, v)
+}
+
+func (t styledTableView) value(col int, row int) (reflect.Value, bool) {
+	return tableCellValue(t.TableView, col, row)
 }
@@ -2,6 +2,8 @@ package cli
 
 import (
 	"bytes"
+	"fmt"
+	"reflect"
 	"testing"
 	"time"
 )
@@ -82,3 +84,298 @@ func TestRenderTableViewFromStructWithAnanymousFields(t *testing.T) {
 
 	t.Logf("\n\n%s\n", buffer.String())
 }
+
+type testLevel int
+
+func (l testLevel) String() string {
+	return [...]string{"low", "high"}[l]
+}
+
+type testAddr struct{ ip [4]byte }
+
+func (a *testAddr) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("%d.%d.%d.%d", a.ip[0], a.ip[1], a.ip[2], a.ip[3])), nil
+}
+
+type testCelsius float64
+
+func TestTableViewCellFormats(t *testing.T) {
+	RegisterCellFormatter(reflect.TypeOf(testCelsius(0)), func(v interface{}) string {
+		return fmt.Sprintf("%.1f°C", float64(v.(testCelsius)))
+	})
+	defer RegisterCellFormatter(reflect.TypeOf(testCelsius(0)), nil)
+
+	type T struct {
+		Date    time.Time   `table:"DATE:,format=2006-01-02"`
+		Time    time.Time   `table:"TIME,default=never"`
+		Ratio   float64     `table:"RATIO,precision=2"`
+		Size    int64       `table:"SIZE,humanize=bytes"`
+		Count   int         `table:"COUNT,humanize=si"`
+		Owner   *string     `table:"OWNER,default=-"`
+		Level   testLevel   `table:"LEVEL"`
+		Addr    testAddr    `table:"ADDR"`
+		Temp    testCelsius `table:"TEMP"`
+		Hex     int         `table:"HEX,format=%#x"`
+		private int
+	}
+
+	owner := "luke"
+	date := time.Date(2017, 6, 24, 12, 30, 0, 0, time.UTC)
+
+	view := NewTableView([]T{
+		{
+			Date:  date,
+			Time:  date,
+			Ratio: 1.0 / 3.0,
+			Size:  1536,
+			Count: 1200,
+			Owner: &owner,
+			Level: 1,
+			Addr:  testAddr{[4]byte{127, 0, 0, 1}},
+			Temp:  21.5,
+			Hex:   255,
+		},
+		{
+			Size:  512,
+			Count: 12,
+		},
+	})
+
+	expected := [][]string{
+		{"2017-06-24", "2017-06-24T12:30:00Z", "0.33", "1.5 KiB", "1.2k", "luke", "high", "127.0.0.1", "21.5°C", "0xff"},
+		{"", "never", "0.00", "512 B", "12", "-", "low", "0.0.0.0", "0.0°C", "0x0"},
+	}
+
+	if cols, rows := view.Size(); cols != 10 || rows != 2 {
+		t.Fatalf("invalid table size: %d x %d", cols, rows)
+	}
+
+	for j, row := range expected {
+		for i, cell := range row {
+			if s := view.Cell(i, j); s != cell {
+				t.Errorf("%s (row %d): expected %q but found %q", view.Column(i), j, cell, s)
+			}
+		}
+	}
+}
+
+func TestTableViewOf(t *testing.T) {
+	type T struct {
+		Name string `table:"NAME"`
+		Size int    `table:"SIZE,default=-"`
+	}
+
+	ch := make(chan T, 2)
+	ch <- T{"a", 1}
+	ch <- T{"b", 2}
+	close(ch)
+
+	tests := []struct {
+		name  string
+		value interface{}
+		cells [][]string
+	}{
+		{
+			name:  "slice of pointers",
+			value: []*T{{"a", 1}, nil, {"c", 3}},
+			cells: [][]string{{"NAME", "SIZE"}, {"a", "1"}, {"", "-"}, {"c", "3"}},
+		},
+		{
+			name:  "map of structs",
+			value: map[string]T{"y": {"b", 2}, "x": {"a", 1}},
+			cells: [][]string{{"KEY", "NAME", "SIZE"}, {"x", "a", "1"}, {"y", "b", "2"}},
+		},
+		{
+			name:  "map of scalars",
+			value: map[string]int{"b": 2, "a": 1},
+			cells: [][]string{{"KEY", "VALUE"}, {"a", "1"}, {"b", "2"}},
+		},
+		{
+			name: "slice of maps",
+			value: []map[string]interface{}{
+				{"name": "a", "size": 1},
+				{"name": "b", "mode": "rw"},
+			},
+			cells: [][]string{{"mode", "name", "size"}, {"", "a", "1"}, {"rw", "b", ""}},
+		},
+		{
+			name:  "slice of scalars",
+			value: []string{"hello", "world"},
+			cells: [][]string{{"VALUE"}, {"hello"}, {"world"}},
+		},
+		{
+			name:  "channel of structs",
+			value: ch,
+			cells: [][]string{{"NAME", "SIZE"}, {"a", "1"}, {"b", "2"}},
+		},
+		{
+			name: "iterator of structs",
+			value: func(yield func(T) bool) {
+				_ = yield(T{"a", 1}) && yield(T{"b", 2})
+			},
+			cells: [][]string{{"NAME", "SIZE"}, {"a", "1"}, {"b", "2"}},
+		},
+	}
+
+	for _, test := range tests {
+		view, err := TableViewOf(test.value)
+
+		if err != nil {
+			t.Errorf("%s: %s", test.name, err)
+			continue
+		}
+
+		cols, rows := view.Size()
+		cells := [][]string{make([]string, cols)}
+
+		for i := 0; i != cols; i++ {
+			cells[0][i] = view.Column(i)
+		}
+
+		for j := 0; j != rows; j++ {
+			row := make([]string, cols)
+
+			for i := 0; i != cols; i++ {
+				row[i] = view.Cell(i, j)
+			}
+
+			cells = append(cells, row)
+		}
+
+		if !reflect.DeepEqual(cells, test.cells) {
+			t.Errorf("%s:\nexpected: %q\nfound:    %q", test.name, test.cells, cells)
+		}
+	}
+}
+
+func TestTableViewQuotedOptions(t *testing.T) {
+	type T struct {
+		Date time.Time `table:"DATE,format='Jan 2, 2006',default='n/a, unknown'"`
+	}
+
+	view := NewTableView([]T{{Date: time.Date(2017, 3, 4, 0, 0, 0, 0, time.UTC)}, {}})
+
+	for row, cell := range []string{"Mar 4, 2017", "n/a, unknown"} {
+		if s := view.Cell(0, row); s != cell {
+			t.Errorf("row %d: expected %q but found %q", row, cell, s)
+		}
+	}
+}
+
+func TestTableViewOfError(t *testing.T) {
+	type T struct {
+		Size int `table:"SIZE,humanize=nope"`
+	}
+
+	type Typo struct {
+		Ratio float64 `table:"RATIO,precison=2"`
+	}
+
+	type Comma struct {
+		Date time.Time `table:"DATE,format=Jan 2, 2006"`
+	}
+
+	for _, v := range []interface{}{nil, 42, map[int]string{}, []T{}, (chan T)(nil), []Typo{}, []Comma{}} {
+		if _, err := TableViewOf(v); err == nil {
+			t.Errorf("%T: expected an error", v)
+		}
+	}
+}
+
+func TestTableViewNestedFields(t *testing.T) {
+	type Address struct {
+		City    string
+		Country string `table:"COUNTRY:"`
+	}
+
+	type User struct {
+		Name    string `table:"NAME"`
+		Email   string `table:"EMAIL"`
+		Address *Address
+	}
+
+	type Base struct {
+		ID int `table:":ID"`
+	}
+
+	type T struct {
+		*Base
+		Title  string `table:"TITLE"`
+		Owner  User   `table:",inline,prefix=OWNER_"`
+		Author *User  `table:"AUTHOR,inline,fields=Name Address.City"`
+		City   *User  `table:"CITY,field=Address.City"`
+		Tags   []string
+	}
+
+	user := &User{Name: "luke", Email: "luke@example.com", Address: &Address{City: "Paris", Country: "FR"}}
+
+	view, err := TableViewOf([]T{
+		{Base: &Base{1}, Title: "first", Owner: *user, Author: user, City: user},
+		{Title: "second"},
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := [][]string{
+		{":ID", "TITLE", "OWNER_NAME", "OWNER_EMAIL", "OWNER_Address", "[AUTHOR]NAME", "[AUTHOR]Address.City", "CITY", "Tags"},
+		{"1", "first", "luke", "luke@example.com", "{Paris FR}", "luke", "Paris", "Paris", "[]"},
+		{"", "second", "", "", "", "", "", "", "[]"},
+	}
+
+	cols, rows := view.Size()
+
+	if cols != len(expected[0]) || rows != len(expected)-1 {
+		t.Fatalf("invalid table size: %d x %d", cols, rows)
+	}
+
+	for i := 0; i != cols; i++ {
+		if s := view.Column(i); s != expected[0][i] {
+			t.Errorf("column %d: expected %q but found %q", i, expected[0][i], s)
+		}
+	}
+
+	for j, row := range expected[1:] {
+		for i, cell := range row {
+			if s := view.Cell(i, j); s != cell {
+				t.Errorf("%s (row %d): expected %q but found %q", view.Column(i), j, cell, s)
+			}
+		}
+	}
+}
+
+func TestTableViewNestedFieldsError(t *testing.T) {
+	type User struct {
+		Name string
+	}
+
+	type Embedded struct {
+		*Embedded
+	}
+
+	type Inlined struct {
+		Name string
+		Next *Inlined `table:"NEXT,inline"`
+	}
+
+	tests := []interface{}{
+		[]struct {
+			Owner User `table:"OWNER,field=Login"`
+		}{},
+		[]Embedded{},
+		[]Inlined{},
+		[]struct {
+			Owner User `table:",inline,fields=Name Login"`
+		}{},
+		[]struct {
+			Owner string `table:",inline"`
+		}{},
+	}
+
+	for _, test := range tests {
+		if _, err := TableViewOf(test); err == nil {
+			t.Errorf("%T: expected an error", test)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+)
+
+// defaultTableWindow is the number of rows that a TableWriter buffers by
+// default before computing the widths of the columns.
+const defaultTableWindow = 100
+
+// TableWriterOptions configures the rendering of tables by a TableWriter.
+type TableWriterOptions struct {
+	TableOptions
+
+	// Window is the number of rows buffered by the writer to compute the
+	// widths of the columns before it starts rendering the table. Zero means
+	// 100 rows, a negative value means the widths are computed from the column
+	// names and options only. Rows appended after the widths were computed
+	// are truncated or wrapped according to the column options.
+	Window int
+
+	// HeaderInterval is the number of rows after which the column names are
+	// rendered again, zero means they are only rendered once.
+	HeaderInterval int
+}
+
+// TableWriter renders tables with an unbounded number of rows, which are
+// written incrementally as they are appended.
+type TableWriter struct {
+	w       io.Writer
+	cols    []string
+	rows    [][]string
+	lines   [][]string
+	options TableWriterOptions
+	layout  *tableLayout
+	count   int
+}
+
+// NewTableWriter returns a TableWriter rendering rows appended to it to w.
+func NewTableWriter(w io.Writer, columns ...string) *TableWriter {
+	return NewTableWriterWithOptions(w, TableWriterOptions{}, columns...)
+}
+
+// NewTableWriterWithOptions is like NewTableWriter but allows the program to
+// configure how the table is laid out.
+func NewTableWriterWithOptions(w io.Writer, options TableWriterOptions, columns ...string) *TableWriter {
+	if options.Window == 0 {
+		options.Window = defaultTableWindow
+	}
+
+	options.MaxWidth = fitWidth(w, options.MaxWidth)
+
+	return &TableWriter{
+		w:       w,
+		cols:    columns,
+		options: options,
+	}
+}
+
+// Append adds a row to the table, the row is written to the underlying writer
+// once the widths of the columns are known. An error is returned if the row
+// doesn't have one cell per column.
+func (t *TableWriter) Append(row ...string) error {
+	if len(t.cols) != len(row) {
+		return fmt.Errorf("cli.(*TableWriter).Append: invalid row length (expected %d but found %d)", len(t.cols), len(row))
+	}
+
+	t.rows = append(t.rows, row)
+
+	if t.layout == nil && len(t.rows) < t.options.Window {
+		return nil
+	}
+
+	return t.writeRows()
+}
+
+// Flush writes the buffered rows, computing the widths of the columns if it
+// wasn't done yet, then flushes the underlying writer if it is a Writer.
+func (t *TableWriter) Flush() error {
+	if err := t.writeRows(); err != nil {
+		return err
+	}
+
+	if f, ok := t.w.(Writer); ok {
+		return f.Flush()
+	}
+
+	return nil
+}
+
+// Close flushes the table and renders its bottom border. Rows must not be
+// appended after the table was closed.
+func (t *TableWriter) Close() error {
+	if err := t.writeRows(); err != nil {
+		return err
+	}
+
+	border := t.options.border()
+
+	if err := border.renderLine(t.w, border.Bottom, t.layout); err != nil {
+		return err
+	}
+
+	return t.Flush()
+}
+
+func (t *TableWriter) writeRows() (err error) {
+	options := &t.options.TableOptions
+	table := escapedTableView(&Table{cols: t.cols, rows: t.rows}, options.border())
+
+	if t.layout == nil {
+		rows := len(t.rows)
+
+		if t.options.Window < 0 {
+			rows = 0
+		}
+
+		t.layout = layoutTableView(table, options, len(t.cols), rows)
+		t.lines = make([][]string, len(t.cols))
+
+		if err = renderTableViewColumns(t.w, table, options, t.layout); err != nil {
+			return
+		}
+	}
+
+	for j := range t.rows {
+		if t.options.HeaderInterval > 0 && t.count != 0 && (t.count%t.options.HeaderInterval) == 0 {
+			if err = t.writeHeader(table); err != nil {
+				return
+			}
+		}
+
+		if err = renderTableViewRow(t.w, table, options, t.layout, t.lines, j, options.rowStyle(t.count)); err != nil {
+			return
+		}
+
+		t.count++
+	}
+
+	t.rows = t.rows[:0]
+	return
+}
+
+func (t *TableWriter) writeHeader(table TableView) (err error) {
+	border := t.options.border()
+
+	if err = border.renderLine(t.w, border.Header, t.layout); err != nil {
+		return
+	}
+
+	if err = renderTableViewHeader(t.w, table, &t.options.TableOptions, t.layout); err != nil {
+		return
+	}
+
+	return border.renderLine(t.w, border.Header, t.layout)
+}
@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestTableWriter(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	table := NewTableWriterWithOptions(buffer, TableWriterOptions{Window: 2}, "LEVEL:", "MESSAGE:")
+
+	table.Append("info", "starting")
+
+	if buffer.Len() != 0 {
+		t.Errorf("rows were written before the window was full:\n%s", buffer.String())
+	}
+
+	table.Append("warn", "slow")
+	table.Append("error", "connection refused")
+	table.Close()
+
+	const output = "" +
+		"LEVEL MESSAGE \n" +
+		"info  starting\n" +
+		"warn  slow    \n" +
+		"error connect…\n"
+
+	if s := buffer.String(); s != output {
+		t.Errorf("\n\n%s\n%q", s, s)
+	}
+}
+
+func TestTableWriterHeaderInterval(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	table := NewTableWriterWithOptions(buffer, TableWriterOptions{
+		TableOptions: TableOptions{
+			Border:  &ASCIIBorder,
+			Columns: []ColumnOptions{{MinWidth: 3}},
+		},
+		Window:         -1,
+		HeaderInterval: 2,
+	}, ":ID")
+
+	for _, id := range []string{"1", "2", "3"} {
+		table.Append(id)
+	}
+
+	table.Close()
+
+	const output = "" +
+		"+-----+\n" +
+		"|  ID |\n" +
+		"+-----+\n" +
+		"|   1 |\n" +
+		"|   2 |\n" +
+		"+-----+\n" +
+		"|  ID |\n" +
+		"+-----+\n" +
+		"|   3 |\n" +
+		"+-----+\n"
+
+	if s := buffer.String(); s != output {
+		t.Errorf("\n\n%s\n%q", s, s)
+	}
+}
+
+func TestTableWriterNoWindow(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	table := NewTableWriterWithOptions(buffer, TableWriterOptions{
+		TableOptions: TableOptions{
+			Columns: []ColumnOptions{{}, {MinWidth: 6}},
+		},
+		Window: -1,
+	}, "ID:", "NAME:")
+
+	table.Append("1234", "hello world")
+	table.Close()
+
+	// The widths come from the column names and options, not from the
+	// first row.
+	const output = "" +
+		"ID NAME  \n" +
+		"1… hello…\n"
+
+	if s := buffer.String(); s != output {
+		t.Errorf("\n\n%s\n%q", s, s)
+	}
+}
+
+func TestTableWriterAppendError(t *testing.T) {
+	table := NewTableWriter(&bytes.Buffer{}, "A", "B")
+
+	if err := table.Append("1"); err == nil {
+		t.Error("expected an error when appending a row with too few cells")
+	}
+
+	if err := table.Append("1", "2", "3"); err == nil {
+		t.Error("expected an error when appending a row with too many cells")
+	}
+}
@@ -0,0 +1,199 @@
+package cli
+
+import "unicode/utf8"
+
+// Ellipsis is the string appended to text truncated to fit a column.
+const Ellipsis = "…"
+
+// Truncate returns s cut to fit within width columns, ending with ellipsis if
+// any text was removed.
+//
+// Escape sequences are never removed, so styles applied to s remain balanced
+// in the truncated string.
+func Truncate(s string, width int, ellipsis string) string {
+	if DisplayWidthInString(s) <= width {
+		return s
+	}
+
+	limit := width - DisplayWidthInString(ellipsis)
+
+	if limit < 0 {
+		limit, ellipsis = width, ""
+	}
+
+	b := make([]byte, 0, len(s)+len(ellipsis))
+	d := displayWidth{}
+	cut := false
+
+	ForEachTokenInString(s, func(t Token) {
+		if t.Kind != TextToken {
+			b = append(b, t.Bytes...)
+			return
+		}
+
+		for text := t.Bytes; !cut && len(text) != 0; {
+			r, n := utf8.DecodeRune(text)
+
+			if d.add(r); d.n > limit {
+				b, cut = append(b, ellipsis...), true
+				break
+			}
+
+			b, text = append(b, text[:n]...), text[n:]
+		}
+	})
+
+	return string(b)
+}
+
+// Wrap splits s in lines that fit within width columns, breaking lines at
+// the last character that fits and at newline characters.
+//
+// Styles that are active at the end of a line are reset and applied again at
+// the beginning of the next line. A width of zero or less only breaks lines at
+// newline characters.
+func Wrap(s string, width int) []string {
+	w := lineWrapper{width: width}
+	w.write([]byte(s))
+	return w.close()
+}
+
+// WordWrap is like Wrap but breaks lines between words, only words that are
+// wider than width are split.
+func WordWrap(s string, width int) []string {
+	w := lineWrapper{width: width}
+
+	word := make([]byte, 0, 64)
+	wordWidth := displayWidth{}
+	spaces := 0
+
+	flush := func() {
+		if wordWidth.n == 0 {
+			// Only escape sequences, they are attached to the current line
+			// without consuming the spaces that may precede the next word.
+			w.write(word)
+			word = word[:0]
+			return
+		}
+
+		if w.d.n != 0 {
+			if width > 0 && (w.d.n+spaces+wordWidth.n) > width {
+				w.breakLine()
+			} else {
+				w.writeSpaces(spaces)
+			}
+		}
+
+		w.write(word)
+		word, wordWidth, spaces = word[:0], displayWidth{}, 0
+	}
+
+	ForEachTokenInString(s, func(t Token) {
+		if t.Kind != TextToken {
+			word = append(word, t.Bytes...)
+			return
+		}
+
+		for text := t.Bytes; len(text) != 0; {
+			r, n := utf8.DecodeRune(text)
+
+			switch r {
+			case ' ':
+				if len(word) != 0 {
+					flush()
+				}
+				spaces++
+			case '\n':
+				flush()
+				w.breakLine()
+				spaces = 0
+			default:
+				word = append(word, text[:n]...)
+				wordWidth.add(r)
+			}
+
+			text = text[n:]
+		}
+	})
+
+	flush()
+	return w.close()
+}
+
+// lineWrapper accumulates text into lines of a maximum display width, keeping
+// track of the styles that need to be carried over from one line to the next.
+type lineWrapper struct {
+	width  int
+	lines  []string
+	line   []byte
+	styles []byte
+	d      displayWidth
+}
+
+func (w *lineWrapper) write(b []byte) {
+	ForEachToken(b, func(t Token) {
+		if t.Kind != TextToken {
+			w.writeSequence(t)
+			return
+		}
+
+		for text := t.Bytes; len(text) != 0; {
+			r, n := utf8.DecodeRune(text)
+			w.writeRune(r, text[:n])
+			text = text[n:]
+		}
+	})
+}
+
+func (w *lineWrapper) writeSequence(t Token) {
+	w.line = append(w.line, t.Bytes...)
+
+	if t.Kind == CSIToken && t.Final() == 'm' {
+		switch string(t.Params()) {
+		case "", "0":
+			w.styles = w.styles[:0]
+		default:
+			w.styles = append(w.styles, t.Bytes...)
+		}
+	}
+}
+
+func (w *lineWrapper) writeRune(r rune, b []byte) {
+	if r == '\n' {
+		w.breakLine()
+		return
+	}
+
+	next := w.d
+	next.add(r)
+
+	if w.width > 0 && next.n > w.width && w.d.n != 0 {
+		w.breakLine()
+		next = displayWidth{}
+		next.add(r)
+	}
+
+	w.line = append(w.line, b...)
+	w.d = next
+}
+
+func (w *lineWrapper) writeSpaces(n int) {
+	if n != 0 {
+		w.line = append(w.line, makeSpaces(n)...)
+		w.d = displayWidth{n: w.d.n + n, last: 1}
+	}
+}
+
+func (w *lineWrapper) breakLine() {
+	if len(w.styles) != 0 {
+		w.line = appendStyleCodes(w.line, 0)
+	}
+
+	w.lines = append(w.lines, string(w.line))
+	w.line = append(w.line[:0], w.styles...)
+	w.d = displayWidth{}
+}
+
+func (w *lineWrapper) close() []string {
+	return append(w.lines, string(w.line))
+}
@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		in    string
+		width int
+		out   string
+	}{
+		{"", 5, ""},
+		{"Hello", 5, "Hello"},
+		{"Hello World!", 8, "Hello W…"},
+		{"日本語のテキスト", 7, "日本語…"},
+		{"Hello World!", 0, ""},
+		{"\033[1mHello World!\033[0m", 6, "\033[1mHello…\033[0m"},
+		{"Hello \033[32mWorld!\033[0m", 4, "Hel…\033[32m\033[0m"},
+	}
+
+	for _, test := range tests {
+		if s := Truncate(test.in, test.width, Ellipsis); s != test.out {
+			t.Errorf("%q (%d): expected %q but found %q", test.in, test.width, test.out, s)
+		}
+	}
+}
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		in    string
+		width int
+		out   []string
+	}{
+		{"", 5, []string{""}},
+		{"Hello", 5, []string{"Hello"}},
+		{"Hello World!", 5, []string{"Hello", " Worl", "d!"}},
+		{"日本語のテキスト", 5, []string{"日本", "語の", "テキ", "スト"}},
+		{"Hello\nWorld!", 10, []string{"Hello", "World!"}},
+		{"\033[1mHello World!\033[0m", 6, []string{"\033[1mHello \033[0m", "\033[1mWorld!\033[0m"}},
+	}
+
+	for _, test := range tests {
+		if lines := Wrap(test.in, test.width); !reflect.DeepEqual(lines, test.out) {
+			t.Errorf("%q (%d): expected %q but found %q", test.in, test.width, test.out, lines)
+		}
+	}
+}
+
+func TestWordWrap(t *testing.T) {
+	tests := []struct {
+		in    string
+		width int
+		out   []string
+	}{
+		{"", 5, []string{""}},
+		{"Hello World!", 20, []string{"Hello World!"}},
+		{"Hello World!", 8, []string{"Hello", "World!"}},
+		{"The quick brown fox jumps", 10, []string{"The quick", "brown fox", "jumps"}},
+		{"Supercalifragilistic word", 10, []string{"Supercalif", "ragilistic", "word"}},
+		{"Hello\nWorld!", 20, []string{"Hello", "World!"}},
+		{"Hello \033[31mWorld!\033[0m", 8, []string{"Hello", "\033[31mWorld!\033[0m"}},
+		{"\033[1mHello World!\033[0m", 8, []string{"\033[1mHello\033[0m", "\033[1mWorld!\033[0m"}},
+	}
+
+	for _, test := range tests {
+		if lines := WordWrap(test.in, test.width); !reflect.DeepEqual(lines, test.out) {
+			t.Errorf("%q (%d): expected %q but found %q", test.in, test.width, test.out, lines)
+		}
+	}
+}
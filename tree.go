@@ -20,62 +20,102 @@ func (t *Tree) Nodes() []TreeView {
 }
 
 type TreeIndent struct {
-	s []rune
+	s     []treeGuide
+	style *TreeStyle
 }
 
+// treeGuide is the state of one level of indentation.
+type treeGuide byte
+
+const (
+	spaceGuide treeGuide = iota
+	verticalGuide
+	branchGuide
+	lastGuide
+)
+
 func NewTreeIndent() *TreeIndent {
+	return NewTreeIndentWithStyle(&LightTree)
+}
+
+// NewTreeIndentWithStyle is like NewTreeIndent but the indentation is drawn
+// with the guides of the given style.
+func NewTreeIndentWithStyle(style *TreeStyle) *TreeIndent {
 	return &TreeIndent{
-		s: make([]rune, 0, 20),
+		s:     make([]treeGuide, 0, 5),
+		style: style,
 	}
 }
 
 func (ti *TreeIndent) Push() int {
-	for i, c := range ti.s {
-		switch c {
-		case '├':
-			ti.s[i] = '│'
-		case '└', '─':
-			ti.s[i] = ' '
+	for i, g := range ti.s {
+		switch g {
+		case branchGuide:
+			ti.s[i] = verticalGuide
+		case lastGuide:
+			ti.s[i] = spaceGuide
 		}
 	}
-	ti.s = append(ti.s, '├', '─', '─', ' ')
-	return len(ti.s) / 4
+	ti.s = append(ti.s, branchGuide)
+	return len(ti.s)
 }
 
 func (ti *TreeIndent) Pop() {
-	ti.s = ti.s[:len(ti.s)-4]
+	ti.s = ti.s[:len(ti.s)-1]
 }
 
 func (ti *TreeIndent) Next(index int, count int, depth int) {
-	depth = (depth - 1) * 4
-
 	if (index + 1) != count {
-		ti.s[depth] = '├'
+		ti.s[depth-1] = branchGuide
 	} else {
-		ti.s[depth] = '└'
+		ti.s[depth-1] = lastGuide
 	}
-
-	ti.s[depth+1] = '─'
-	ti.s[depth+2] = '─'
 }
 
 func (ti *TreeIndent) Clear(index int, depth int) {
-	if depth = (depth - 1) * 4; depth >= 0 && index != 0 {
+	if depth--; depth >= 0 && index != 0 {
 		switch ti.s[depth] {
-		case '└':
-			ti.s[depth] = ' '
-		case '├':
-			ti.s[depth] = '│'
+		case lastGuide:
+			ti.s[depth] = spaceGuide
+		case branchGuide:
+			ti.s[depth] = verticalGuide
 		}
-		ti.s[depth+1] = ' '
-		ti.s[depth+2] = ' '
 	}
 }
 
 func (ti *TreeIndent) Depth() int {
-	return len(ti.s) / 4
+	return len(ti.s)
+}
+
+// Width returns the number of columns occupied by the indentation when it is
+// displayed on a terminal.
+func (ti *TreeIndent) Width() int {
+	return DisplayWidthInString(ti.String())
 }
 
 func (ti *TreeIndent) String() string {
-	return string(ti.s)
+	if len(ti.s) == 0 {
+		return ""
+	}
+
+	b := make([]byte, 0, 16*len(ti.s))
+
+	for _, g := range ti.s {
+		switch g {
+		case spaceGuide:
+			b = append(b, ti.style.Space...)
+		case verticalGuide:
+			b = append(b, ti.style.Vertical...)
+		case branchGuide:
+			b = append(b, ti.style.Branch...)
+		case lastGuide:
+			b = append(b, ti.style.Last...)
+		}
+	}
+
+	if len(ti.style.Style) != 0 {
+		return ti.style.Style.S(string(b))
+	}
+
+	return string(b)
 }
@@ -0,0 +1,84 @@
+package tree
+
+import (
+	"strconv"
+
+	"github.com/achille-roussel/cli"
+)
+
+// DocumentConfig configures the styles of the trees decoded from JSON or YAML
+// documents.
+type DocumentConfig struct {
+	KeyStyle    cli.StyleSet
+	StringStyle cli.StyleSet
+	NumberStyle cli.StyleSet
+	BoolStyle   cli.StyleSet
+	NullStyle   cli.StyleSet
+}
+
+var (
+	DefaultDocumentConfig DocumentConfig = DocumentConfig{
+		KeyStyle:    cli.Style(cli.Bold, cli.Blue),
+		StringStyle: cli.Green,
+		NumberStyle: cli.Cyan,
+		BoolStyle:   cli.Yellow,
+		NullStyle:   cli.Magenta,
+	}
+)
+
+// LeafKind is the type of the scalar values of documents.
+type LeafKind int
+
+const (
+	StringLeaf LeafKind = iota
+	NumberLeaf
+	BoolLeaf
+	NullLeaf
+)
+
+// Node returns a tree node for a container value, label is the key or index
+// of the value in its parent, or empty for the root of a document which is
+// then labeled root.
+//
+// Node and Leaf are used by decoders of other document formats to build trees
+// which look like the ones of JSONDecoder.
+func (c *DocumentConfig) Node(label string, root string, nodes []cli.TreeView) cli.TreeView {
+	if len(label) == 0 {
+		return cli.NewTree(root, nodes...)
+	}
+	return cli.NewTree(styled(c.KeyStyle, label), nodes...)
+}
+
+// Leaf returns a tree node for a scalar value, rendered as "key: value".
+func (c *DocumentConfig) Leaf(label string, kind LeafKind, value string) cli.TreeView {
+	var style cli.StyleSet
+
+	switch kind {
+	case StringLeaf:
+		style, value = c.StringStyle, strconv.Quote(value)
+	case NumberLeaf:
+		style = c.NumberStyle
+	case BoolLeaf:
+		style = c.BoolStyle
+	case NullLeaf:
+		style = c.NullStyle
+	}
+
+	if value = styled(style, value); len(label) != 0 {
+		value = styled(c.KeyStyle, label) + ": " + value
+	}
+
+	return cli.NewTree(value)
+}
+
+func styled(style cli.StyleSet, s string) string {
+	if len(style) == 0 {
+		return s
+	}
+	return style.S(s)
+}
+
+// IndexLabel returns the label of the element at index i of an array.
+func IndexLabel(i int) string {
+	return "[" + strconv.Itoa(i) + "]"
+}
@@ -0,0 +1,173 @@
+package tree
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// gitignore is a list of rules loaded from the .gitignore files of a directory
+// and its parents, the rules of the innermost directories come last.
+type gitignore struct {
+	rules []gitignoreRule
+}
+
+type gitignoreRule struct {
+	base     string // directory of the .gitignore file
+	pattern  string
+	negate   bool
+	dirOnly  bool
+	anchored bool
+}
+
+// load returns the rules of g extended with the rules of the .gitignore file in
+// dir, or g itself if there are none.
+func (g *gitignore) load(dir string) *gitignore {
+	f, err := os.Open(filepath.Join(dir, ".gitignore"))
+	if err != nil {
+		return g
+	}
+	defer f.Close()
+
+	next := &gitignore{}
+
+	if g != nil {
+		next.rules = append(next.rules, g.rules...)
+	}
+
+	s := bufio.NewScanner(f)
+
+	for s.Scan() {
+		line := strings.TrimRight(s.Text(), " \t\r")
+
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+
+		r := gitignoreRule{base: dir}
+
+		if line[0] == '!' {
+			r.negate, line = true, line[1:]
+		} else if line[0] == '\\' {
+			line = line[1:]
+		}
+
+		if strings.HasSuffix(line, "/") {
+			r.dirOnly, line = true, strings.TrimRight(line, "/")
+		}
+
+		if strings.HasPrefix(line, "/") {
+			r.anchored, line = true, line[1:]
+		} else if strings.Contains(line, "/") {
+			r.anchored = true
+		}
+
+		if len(line) != 0 {
+			r.pattern = line
+			next.rules = append(next.rules, r)
+		}
+	}
+
+	return next
+}
+
+// ignored returns true if the file at the given path is ignored by the rules.
+func (g *gitignore) ignored(name string, isDir bool) bool {
+	if g == nil {
+		return false
+	}
+
+	ignored := false
+
+	for _, r := range g.rules {
+		if r.dirOnly && !isDir {
+			continue
+		}
+
+		rel, err := filepath.Rel(r.base, name)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+
+		rel = filepath.ToSlash(rel)
+
+		if !r.anchored {
+			rel = path.Base(rel)
+		}
+
+		if matchGlob(r.pattern, rel) {
+			ignored = !r.negate
+		}
+	}
+
+	return ignored
+}
+
+// gitignoreParents returns the rules of the .gitignore files found in the
+// parents of dir, up to the root of the git repository which contains it. The
+// rules of dir itself are loaded when listing it. There are no rules when dir
+// is not in a git repository.
+func gitignoreParents(dir string) *gitignore {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil
+	}
+
+	var parents []string
+
+	for d := abs; ; {
+		if _, err := os.Stat(filepath.Join(d, ".git")); err == nil {
+			break
+		}
+
+		p := filepath.Dir(d)
+
+		if p == d {
+			return nil
+		}
+
+		d = p
+		parents = append(parents, d)
+	}
+
+	var g *gitignore
+
+	for i := len(parents) - 1; i >= 0; i-- {
+		g = g.load(parents[i])
+	}
+
+	return g
+}
+
+// matchGlob matches name against pattern, where "**" matches any number of
+// path segments and other segments are matched with path.Match.
+func matchGlob(pattern string, name string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pattern []string, name []string) bool {
+	for len(pattern) != 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(name) == 0 {
+			return false
+		}
+
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+
+		pattern, name = pattern[1:], name[1:]
+	}
+
+	return len(name) == 0
+}
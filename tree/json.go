@@ -0,0 +1,118 @@
+package tree
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/achille-roussel/cli"
+)
+
+// JSONDecoder reads JSON documents from a stream and decodes them into trees,
+// where the keys of objects and the indexes of arrays label the nodes. Keys are
+// kept in the order they appear in the documents.
+type JSONDecoder struct {
+	d      *json.Decoder
+	config DocumentConfig
+}
+
+func NewJSONDecoder(r io.Reader) *JSONDecoder {
+	return NewJSONDecoderWithConfig(r, DefaultDocumentConfig)
+}
+
+func NewJSONDecoderWithConfig(r io.Reader, config DocumentConfig) *JSONDecoder {
+	d := json.NewDecoder(r)
+	d.UseNumber()
+	return &JSONDecoder{d: d, config: config}
+}
+
+// Decode returns the tree of the next document of the stream, or io.EOF when
+// all documents have been read.
+func (d *JSONDecoder) Decode() (cli.TreeView, error) {
+	tok, err := d.d.Token()
+	if err != nil {
+		return nil, err
+	}
+	return d.decode("", tok)
+}
+
+func (d *JSONDecoder) decode(label string, tok json.Token) (node cli.TreeView, err error) {
+	switch v := tok.(type) {
+	case json.Delim:
+		var nodes []cli.TreeView
+		var root string
+
+		switch v {
+		case '{':
+			root = "{}"
+
+			for d.d.More() {
+				var key json.Token
+				var child cli.TreeView
+
+				if key, err = d.d.Token(); err != nil {
+					return
+				}
+
+				if tok, err = d.d.Token(); err != nil {
+					return
+				}
+
+				name := key.(string)
+
+				if len(name) == 0 {
+					// Empty labels are reserved for the root of documents.
+					name = `""`
+				}
+
+				if child, err = d.decode(name, tok); err != nil {
+					return
+				}
+
+				nodes = append(nodes, child)
+			}
+
+		case '[':
+			root = "[]"
+
+			for i := 0; d.d.More(); i++ {
+				var child cli.TreeView
+
+				if tok, err = d.d.Token(); err != nil {
+					return
+				}
+
+				if child, err = d.decode(IndexLabel(i), tok); err != nil {
+					return
+				}
+
+				nodes = append(nodes, child)
+			}
+
+		default:
+			err = fmt.Errorf("tree.(*JSONDecoder).Decode: unexpected delimiter %s", v)
+			return
+		}
+
+		// Consume the closing delimiter of the object or array.
+		if _, err = d.d.Token(); err != nil {
+			return
+		}
+
+		node = d.config.Node(label, root, nodes)
+
+	case string:
+		node = d.config.Leaf(label, StringLeaf, v)
+
+	case json.Number:
+		node = d.config.Leaf(label, NumberLeaf, v.String())
+
+	case bool:
+		node = d.config.Leaf(label, BoolLeaf, fmt.Sprint(v))
+
+	case nil:
+		node = d.config.Leaf(label, NullLeaf, "null")
+	}
+
+	return
+}
@@ -0,0 +1,104 @@
+package tree
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/achille-roussel/cli"
+)
+
+type documentDecoder interface {
+	Decode() (cli.TreeView, error)
+}
+
+// renderDocuments renders all the documents read by d, one after the other.
+func renderDocuments(d documentDecoder) (string, error) {
+	buffer := &bytes.Buffer{}
+
+	for {
+		doc, err := d.Decode()
+
+		if err == io.EOF {
+			return buffer.String(), nil
+		}
+
+		if err != nil {
+			return buffer.String(), err
+		}
+
+		if err := cli.RenderTreeView(buffer, doc); err != nil {
+			return buffer.String(), err
+		}
+	}
+}
+
+func TestJSONDecoder(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		config DocumentConfig
+		output string
+	}{
+		{
+			name:   "Leaves",
+			input:  `{"s":"hello","n":4.2,"b":true,"z":null}`,
+			output: "{}\n├── s: \"hello\"\n├── n: 4.2\n├── b: true\n└── z: null\n",
+		},
+		{
+			name:   "Styled Leaves",
+			input:  `{"s":"a","n":1,"b":false,"z":null}`,
+			config: DefaultDocumentConfig,
+			output: "" +
+				"{}\n" +
+				"├── \x1b[1;34ms\x1b[0m: \x1b[32m\"a\"\x1b[0m\n" +
+				"├── \x1b[1;34mn\x1b[0m: \x1b[36m1\x1b[0m\n" +
+				"├── \x1b[1;34mb\x1b[0m: \x1b[33mfalse\x1b[0m\n" +
+				"└── \x1b[1;34mz\x1b[0m: \x1b[35mnull\x1b[0m\n",
+		},
+		{
+			name:   "Array Indexes",
+			input:  `[1,["a"],{}]`,
+			output: "[]\n├── [0]: 1\n├── [1]\n│   └── [0]: \"a\"\n└── [2]\n",
+		},
+		{
+			name:   "Key Order",
+			input:  `{"b":1,"a":{"":2}}`,
+			output: "{}\n├── b: 1\n└── a\n    └── \"\": 2\n",
+		},
+		{
+			name:   "Scalar Document",
+			input:  `"hello"`,
+			output: "\"hello\"\n",
+		},
+		{
+			name:   "Multiple Documents",
+			input:  "{\"a\":1}\n[true]\n",
+			output: "{}\n└── a: 1\n[]\n└── [0]: true\n",
+		},
+	}
+
+	for _, test := range tests {
+		s, err := renderDocuments(NewJSONDecoderWithConfig(strings.NewReader(test.input), test.config))
+
+		if err != nil {
+			t.Errorf("%s: %s", test.name, err)
+		} else if s != test.output {
+			t.Errorf("%s:\n\n%s\n%q", test.name, s, s)
+		}
+	}
+}
+
+func TestJSONDecoderError(t *testing.T) {
+	for _, input := range []string{
+		`{"a":}`,
+		`[1,2`,
+		`{"a" 1}`,
+		`}`,
+	} {
+		if _, err := renderDocuments(NewJSONDecoder(strings.NewReader(input))); err == nil {
+			t.Errorf("%s: expected an error on malformed input", input)
+		}
+	}
+}
@@ -1,19 +1,144 @@
 package tree
 
 import (
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/achille-roussel/cli"
 )
 
+// PathConfig configures the trees returned by PathWithConfig.
+//
+// The depth of path trees is not part of the configuration, it is limited when
+// rendering them with the MaxDepth field of cli.TreeOptions, like the -L flag
+// of cmd/tree does. The directories deeper than the limit are not listed.
 type PathConfig struct {
 	DirStyle      cli.StyleSet
 	RegFileStyle  cli.StyleSet
 	ExecFileStyle cli.StyleSet
 	ShowHidden    bool
+
+	// Columns lists the file attributes rendered beside the tree when it is
+	// rendered with cli.RenderTreeTableView, see ColumnSpecs.
+	Columns []PathColumn
+
+	// HumanizeSizes renders sizes with units, like 4.0K.
+	HumanizeSizes bool
+
+	// DirSizes renders the size of directories as the sum of the sizes of the
+	// files listed under them, so the filters of the configuration apply.
+	DirSizes bool
+
+	// TimeFormat is the layout of modification times, "Jan _2 15:04" when
+	// empty.
+	TimeFormat string
+
+	// Include lists glob patterns matched against the names of files, only
+	// the files matching one of them are listed when it is not empty.
+	// Directories are always listed.
+	Include []string
+
+	// Exclude lists glob patterns matched against the names of files and
+	// directories which are not listed.
+	Exclude []string
+
+	// GitIgnore hides the files ignored by the .gitignore files found in the
+	// listed directories, and in their parents up to the root of the git
+	// repository.
+	GitIgnore bool
+
+	// DirsOnly lists directories only.
+	DirsOnly bool
+
+	// FollowSymlinks lists the content of symbolic links to directories as if
+	// they were directories. Links which point to one of their parents are
+	// not followed.
+	FollowSymlinks bool
+
+	// PruneEmpty hides the directories which have no files to list.
+	PruneEmpty bool
+
+	// SortBy defines the order of the files listed in directories.
+	SortBy PathSort
+
+	// DirsFirst lists directories before files.
+	DirsFirst bool
+
+	// Reverse lists files in reverse order, directories still come first when
+	// DirsFirst is set.
+	Reverse bool
+}
+
+// PathStats counts the directories and files listed in path trees, not
+// including their roots.
+type PathStats struct {
+	Dirs  int
+	Files int
+}
+
+// Count adds node to the statistics if it is a node of a path tree, it is
+// meant to be called for each node rendered, for example by the Filter of
+// cli.TreeOptions.
+func (s *PathStats) Count(node cli.TreeView) {
+	if f, ok := node.(file); ok {
+		if f.info.IsDir() {
+			s.Dirs++
+		} else {
+			s.Files++
+		}
+	}
+}
+
+// String returns a summary of s like the one printed by tree(1).
+func (s PathStats) String() string {
+	return plural(s.Dirs, "directory", "directories") + ", " + plural(s.Files, "file", "files")
+}
+
+func plural(n int, one string, many string) string {
+	if n == 1 {
+		return "1 " + one
+	}
+	return strconv.Itoa(n) + " " + many
+}
+
+// PathColumn represents the file attributes that can be rendered in columns
+// beside the trees returned by Path.
+type PathColumn int
+
+const (
+	SizeColumn PathColumn = iota
+	ModeColumn
+	ModTimeColumn
+)
+
+// Spec returns the column spec of c.
+func (c PathColumn) Spec() string {
+	switch c {
+	case SizeColumn:
+		return ":SIZE"
+	case ModeColumn:
+		return "MODE:"
+	case ModTimeColumn:
+		return "MODIFIED:"
+	default:
+		return ""
+	}
+}
+
+// ColumnSpecs returns the specs of the columns of config, to be passed to
+// cli.RenderTreeTableView.
+func (config *PathConfig) ColumnSpecs() []string {
+	specs := make([]string, len(config.Columns))
+
+	for i, c := range config.Columns {
+		specs[i] = c.Spec()
+	}
+
+	return specs
 }
 
 var (
@@ -29,15 +154,34 @@ func Path(info os.FileInfo, path string) cli.TreeView {
 	return PathWithConfig(info, path, DefaultPathConfig)
 }
 
+// PathWithConfig returns a tree of the file at path, listing the content of
+// directories as configured by config. Use cli.TreeOptions to limit the depth
+// of the tree when rendering it.
 func PathWithConfig(info os.FileInfo, path string, config PathConfig) cli.TreeView {
-	return makeFile(info, path, path, &config)
+	f := makeFile(info, path, path, &config)
+
+	if config.DirSizes {
+		f.sizes = make(map[interface{}]int64)
+	}
+
+	if config.GitIgnore {
+		f.ignore = gitignoreParents(path)
+	}
+
+	return f
 }
 
 type file struct {
 	path   string
 	name   string
+	link   string
 	info   os.FileInfo
+	ignore *gitignore
 	config *PathConfig
+
+	// sizes holds the sizes of the directories of the tree, shared by all
+	// its nodes so each directory is listed once to compute them.
+	sizes map[interface{}]int64
 }
 
 func makeFile(info os.FileInfo, name string, path string, config *PathConfig) file {
@@ -58,23 +202,225 @@ func (f file) Cell() string {
 		style = f.config.ExecFileStyle
 	}
 
-	return style.S(f.name)
+	if len(f.link) != 0 {
+		return styled(style, f.name) + " -> " + f.link
+	}
+
+	return styled(style, f.name)
+}
+
+// Key returns the real path of f, which is used to detect the cycles created
+// by symbolic links.
+func (f file) Key() interface{} {
+	if f.config.FollowSymlinks {
+		if path, err := filepath.EvalSymlinks(f.path); err == nil {
+			return path
+		}
+	}
+	return f.path
+}
+
+func (f file) ColumnCell(col int) string {
+	if col >= len(f.config.Columns) {
+		return ""
+	}
+
+	switch f.config.Columns[col] {
+	case SizeColumn:
+		return f.size()
+	case ModeColumn:
+		return f.info.Mode().String()
+	case ModTimeColumn:
+		layout := f.config.TimeFormat
+
+		if len(layout) == 0 {
+			layout = "Jan _2 15:04"
+		}
+
+		return f.info.ModTime().Format(layout)
+	default:
+		return ""
+	}
+}
+
+func (f file) size() string {
+	size := f.info.Size()
+
+	if f.info.IsDir() && f.config.DirSizes {
+		size = f.dirSize()
+	}
+
+	if f.config.HumanizeSizes {
+		return humanizeSize(size)
+	}
+
+	return strconv.FormatInt(size, 10)
+}
+
+// dirSize returns the sum of the sizes of the files listed under f, computed
+// from the sizes of its children.
+func (f file) dirSize() (size int64) {
+	key := f.Key()
+
+	if size, ok := f.sizes[key]; ok {
+		return size
+	}
+
+	// The directory is marked before listing it so symbolic links to one of
+	// its parents add nothing.
+	f.sizes[key] = 0
+
+	f.each(nil, func(child file) bool {
+		if child.info.IsDir() {
+			size += child.dirSize()
+		} else {
+			size += child.info.Size()
+		}
+		return true
+	})
+
+	f.sizes[key] = size
+	return
+}
+
+// humanizeSize formats n like the -h option of tree(1) does, with one decimal
+// below ten units.
+func humanizeSize(n int64) string {
+	const units = "KMGTPE"
+
+	if n < 1024 {
+		return strconv.FormatInt(n, 10)
+	}
+
+	v := float64(n)
+	u := -1
+
+	for v >= 1024 && u+1 < len(units) {
+		v /= 1024
+		u++
+	}
+
+	if v < 10 {
+		return fmt.Sprintf("%.1f%c", v, units[u])
+	}
+
+	return fmt.Sprintf("%.0f%c", v, units[u])
 }
 
 func (f file) Nodes() (nodes []cli.TreeView) {
-	if f.info.IsDir() {
-		files, _ := ioutil.ReadDir(f.path)
-		nodes = make([]cli.TreeView, 0, len(files))
+	files := f.list(nil)
+	nodes = make([]cli.TreeView, len(files))
+
+	for i, child := range files {
+		nodes[i] = child
+	}
+
+	return
+}
+
+// list returns the sorted children of f which pass the filters of the
+// configuration.
+func (f file) list(parents map[interface{}]bool) (files []file) {
+	f.each(parents, func(child file) bool {
+		files = append(files, child)
+		return true
+	})
+	sortFiles(files, f.config)
+	return
+}
+
+// each calls do for the children of f which pass the filters of the
+// configuration, until do returns false. parents holds the keys of the
+// directories being checked for emptiness, to stop at cycles.
+func (f file) each(parents map[interface{}]bool, do func(file) bool) {
+	config := f.config
 
-		for _, info := range files {
-			name := info.Name()
-			path := filepath.Join(f.path, name)
+	if !f.info.IsDir() {
+		return
+	}
+
+	infos, _ := ioutil.ReadDir(f.path)
+	ignore := f.ignore
+	dir := f.path
+
+	if config.GitIgnore {
+		// Rules are matched against absolute paths since they may come
+		// from the parents of the root.
+		if abs, err := filepath.Abs(f.path); err == nil {
+			dir = abs
+		}
+		ignore = ignore.load(dir)
+	}
+
+	for _, info := range infos {
+		name := info.Name()
+		child := makeFile(info, name, filepath.Join(f.path, name), config)
+		child.ignore = ignore
+		child.sizes = f.sizes
+
+		if !config.ShowHidden && strings.HasPrefix(name, ".") {
+			continue
+		}
 
-			if f.config.ShowHidden || !strings.HasPrefix(name, ".") {
-				nodes = append(nodes, makeFile(info, name, path, f.config))
+		if (info.Mode() & os.ModeSymlink) != 0 {
+			child.link, _ = os.Readlink(child.path)
+
+			if config.FollowSymlinks {
+				if target, err := os.Stat(child.path); err == nil {
+					child.info = target
+				}
 			}
 		}
+
+		isDir := child.info.IsDir()
+
+		if matchAny(config.Exclude, name) || ignore.ignored(filepath.Join(dir, name), isDir) {
+			continue
+		}
+
+		if !isDir && (config.DirsOnly || (len(config.Include) != 0 && !matchAny(config.Include, name))) {
+			continue
+		}
+
+		if isDir && config.PruneEmpty && child.empty(parents) {
+			continue
+		}
+
+		if !do(child) {
+			return
+		}
+	}
+}
+
+// empty returns true if f has no files to list, directories which point to one
+// of their parents are not empty.
+func (f file) empty(parents map[interface{}]bool) bool {
+	key := f.Key()
+
+	if parents[key] {
+		return false
 	}
 
-	return
+	if parents == nil {
+		parents = make(map[interface{}]bool)
+	}
+
+	parents[key] = true
+	defer delete(parents, key)
+
+	empty := true
+	f.each(parents, func(file) bool {
+		empty = false
+		return false
+	})
+	return empty
+}
+
+func matchAny(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if ok, _ := filepath.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,403 @@
+package tree
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/achille-roussel/cli"
+)
+
+// makeFixture creates the files in a temporary directory, names ending with a
+// slash are directories and names containing " -> " are symbolic links.
+func makeFixture(t *testing.T, files ...string) string {
+	root, err := ioutil.TempDir("", "tree-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range files {
+		path := filepath.Join(root, strings.Split(name, " -> ")[0])
+
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		switch {
+		case strings.Contains(name, " -> "):
+			err = os.Symlink(strings.Split(name, " -> ")[1], path)
+		case strings.HasSuffix(name, "/"):
+			err = os.MkdirAll(path, 0755)
+		default:
+			err = ioutil.WriteFile(path, []byte(name), 0644)
+		}
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return root
+}
+
+// renderPath renders the tree of path without its first line, which holds the
+// name of the temporary directory.
+func renderPath(t *testing.T, path string, config PathConfig, options cli.TreeOptions) string {
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buffer := &bytes.Buffer{}
+
+	if err := cli.RenderTreeViewWithOptions(buffer, PathWithConfig(info, path, config), options); err != nil {
+		t.Fatal(err)
+	}
+
+	s := buffer.String()
+	return s[strings.IndexByte(s, '\n')+1:]
+}
+
+func TestPathFilters(t *testing.T) {
+	root := makeFixture(t,
+		"a.go",
+		"b.txt",
+		".hidden",
+		"dir/c.go",
+		"dir/d.txt",
+		"empty/",
+		"txt/e.txt",
+	)
+	defer os.RemoveAll(root)
+
+	tests := []struct {
+		name    string
+		config  PathConfig
+		options cli.TreeOptions
+		output  string
+	}{
+		{
+			name:   "Default",
+			output: "├── a.go\n├── b.txt\n├── dir\n│   ├── c.go\n│   └── d.txt\n├── empty\n└── txt\n    └── e.txt\n",
+		},
+		{
+			name:   "Hidden",
+			config: PathConfig{ShowHidden: true},
+			output: "├── .hidden\n├── a.go\n├── b.txt\n├── dir\n│   ├── c.go\n│   └── d.txt\n├── empty\n└── txt\n    └── e.txt\n",
+		},
+		{
+			name:   "Include",
+			config: PathConfig{Include: []string{"*.go"}},
+			output: "├── a.go\n├── dir\n│   └── c.go\n├── empty\n└── txt\n",
+		},
+		{
+			name:   "Include Prune Empty",
+			config: PathConfig{Include: []string{"*.go"}, PruneEmpty: true},
+			output: "├── a.go\n└── dir\n    └── c.go\n",
+		},
+		{
+			name:   "Exclude",
+			config: PathConfig{Exclude: []string{"dir", "*.txt"}},
+			output: "├── a.go\n├── empty\n└── txt\n",
+		},
+		{
+			name:   "Dirs Only",
+			config: PathConfig{DirsOnly: true},
+			output: "├── dir\n├── empty\n└── txt\n",
+		},
+		{
+			name:    "Max Depth",
+			options: cli.TreeOptions{MaxDepth: 1},
+			output:  "├── a.go\n├── b.txt\n├── dir\n├── empty\n└── txt\n",
+		},
+	}
+
+	for _, test := range tests {
+		if s := renderPath(t, root, test.config, test.options); s != test.output {
+			t.Errorf("%s:\n\n%s\n%q", test.name, s, s)
+		}
+	}
+}
+
+func TestPathSymlinkCycle(t *testing.T) {
+	root := makeFixture(t,
+		"a/b",
+		"a/up -> ..",
+		"c/self -> .",
+	)
+	defer os.RemoveAll(root)
+
+	tests := []struct {
+		name   string
+		config PathConfig
+		output string
+	}{
+		{
+			name:   "Not Followed",
+			output: "├── a\n│   ├── b\n│   └── up -> ..\n└── c\n    └── self -> .\n",
+		},
+		{
+			name:   "Followed",
+			config: PathConfig{FollowSymlinks: true},
+			output: "├── a\n│   ├── b\n│   └── up -> .. <cycle>\n└── c\n    └── self -> . <cycle>\n",
+		},
+		{
+			name:   "Followed Prune Empty",
+			config: PathConfig{FollowSymlinks: true, PruneEmpty: true},
+			output: "├── a\n│   ├── b\n│   └── up -> .. <cycle>\n└── c\n    └── self -> . <cycle>\n",
+		},
+	}
+
+	for _, test := range tests {
+		if s := renderPath(t, root, test.config, cli.TreeOptions{}); s != test.output {
+			t.Errorf("%s:\n\n%s\n%q", test.name, s, s)
+		}
+	}
+}
+
+func TestPathGitIgnore(t *testing.T) {
+	root := makeFixture(t,
+		".git/",
+		".gitignore",
+		"a.log",
+		"keep.log",
+		"build/x",
+		"tmp/z",
+		"sub/.gitignore",
+		"sub/build/y",
+		"sub/tmp",
+		"sub/debug.log",
+		"sub/keep.log",
+		"sub/other.log",
+	)
+	defer os.RemoveAll(root)
+
+	write := func(name string, rules string) {
+		if err := ioutil.WriteFile(filepath.Join(root, name), []byte(rules), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write(".gitignore", "# comment\n*.log\n!keep.log\n/build\ntmp/\n")
+	write("sub/.gitignore", "!debug.log\nkeep.log\n")
+
+	config := PathConfig{GitIgnore: true}
+
+	if s := renderPath(t, root, config, cli.TreeOptions{}); s != ""+
+		"├── keep.log\n"+
+		"└── sub\n"+
+		"    ├── build\n"+
+		"    │   └── y\n"+
+		"    ├── debug.log\n"+
+		"    └── tmp\n" {
+		t.Errorf("root:\n\n%s\n%q", s, s)
+	}
+
+	// The rules of the parents apply when listing a sub-directory of the
+	// repository.
+	if s := renderPath(t, filepath.Join(root, "sub"), config, cli.TreeOptions{}); s != ""+
+		"├── build\n"+
+		"│   └── y\n"+
+		"├── debug.log\n"+
+		"└── tmp\n" {
+		t.Errorf("sub:\n\n%s\n%q", s, s)
+	}
+}
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		match   bool
+	}{
+		{"*.go", "a.go", true},
+		{"*.go", "a.txt", false},
+		{"a/*.go", "a/b.go", true},
+		{"a/*.go", "a/b/c.go", false},
+		{"**/c.go", "c.go", true},
+		{"**/c.go", "a/b/c.go", true},
+		{"a/**", "a/b/c", true},
+		{"a/**/c", "a/c", true},
+		{"a/**/c", "a/b/d/c", true},
+		{"a/**/c", "b/c", false},
+		{"a/**/c", "a/b/d", false},
+	}
+
+	for _, test := range tests {
+		if match := matchGlob(test.pattern, test.name); match != test.match {
+			t.Errorf("%s %s: expected %t but found %t", test.pattern, test.name, test.match, match)
+		}
+	}
+}
+
+func TestGitIgnoreRules(t *testing.T) {
+	root := makeFixture(t, ".gitignore", "sub/.gitignore")
+	defer os.RemoveAll(root)
+
+	ioutil.WriteFile(filepath.Join(root, ".gitignore"), []byte("*.o\n!main.o\n/out\nlogs/\ndocs/*.md\n\\!bang\n"), 0644)
+	ioutil.WriteFile(filepath.Join(root, "sub/.gitignore"), []byte("main.o\n"), 0644)
+
+	g := (*gitignore)(nil).load(root)
+	s := g.load(filepath.Join(root, "sub"))
+
+	tests := []struct {
+		rules   *gitignore
+		name    string
+		isDir   bool
+		ignored bool
+	}{
+		{g, "a.o", false, true},
+		{g, "x/a.o", false, true},
+		{g, "main.o", false, false},
+		{g, "out", true, true},
+		{g, "x/out", true, false},
+		{g, "logs", true, true},
+		{g, "logs", false, false},
+		{g, "docs/a.md", false, true},
+		{g, "x/docs/a.md", false, false},
+		{g, "!bang", false, true},
+		{s, "sub/main.o", false, true},
+		{s, "main.o", false, false},
+		{s, "../main.o", false, false},
+		{s, "..o", false, true},
+	}
+
+	for _, test := range tests {
+		if ignored := test.rules.ignored(filepath.Join(root, test.name), test.isDir); ignored != test.ignored {
+			t.Errorf("%s: expected ignored=%t but found %t", test.name, test.ignored, ignored)
+		}
+	}
+}
+
+func TestPathStats(t *testing.T) {
+	root := makeFixture(t,
+		"a",
+		"b/c",
+		"b/d/e",
+		"f/",
+	)
+	defer os.RemoveAll(root)
+
+	var stats PathStats
+
+	renderPath(t, root, PathConfig{}, cli.TreeOptions{
+		Filter: func(node cli.TreeView) bool {
+			stats.Count(node)
+			return true
+		},
+	})
+
+	if s := stats.String(); s != "3 directories, 3 files" {
+		t.Errorf("unexpected stats: %s", s)
+	}
+
+	stats = PathStats{}
+
+	renderPath(t, root, PathConfig{}, cli.TreeOptions{
+		MaxDepth: 1,
+		Filter: func(node cli.TreeView) bool {
+			stats.Count(node)
+			return true
+		},
+	})
+
+	if s := stats.String(); s != "2 directories, 1 file" {
+		t.Errorf("unexpected stats with a maximum depth: %s", s)
+	}
+}
+
+func TestPathColumns(t *testing.T) {
+	root := makeFixture(t,
+		"a",
+		"d/bb",
+		"d/e/ccccc",
+		"x/",
+	)
+	defer os.RemoveAll(root)
+
+	mtime := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err == nil {
+			if info.IsDir() {
+				os.Chmod(path, 0755)
+			} else {
+				os.Chmod(path, 0644)
+			}
+		}
+		return err
+	})
+
+	// Times are set last since creating files changes the times of their
+	// directories.
+	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err == nil {
+			os.Chtimes(path, mtime, mtime)
+		}
+		return err
+	})
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(cwd)
+
+	// The root is rendered as "." so the width of the tree doesn't depend on
+	// the name of the temporary directory.
+	if err := os.Chdir(root); err != nil {
+		t.Fatal(err)
+	}
+
+	columns := []PathColumn{SizeColumn, ModeColumn, ModTimeColumn}
+
+	tests := []struct {
+		name   string
+		config PathConfig
+		output string
+	}{
+		{
+			name:   "Dir Sizes",
+			config: PathConfig{Columns: columns, DirSizes: true, TimeFormat: "2006-01-02"},
+			output: "" +
+				"                  SIZE MODE       MODIFIED\n" +
+				".                   14 drwxr-xr-x 2020-01-02\n" +
+				"├── a                1 -rw-r--r-- 2020-01-02\n" +
+				"├── d               13 drwxr-xr-x 2020-01-02\n" +
+				"│   ├── bb           4 -rw-r--r-- 2020-01-02\n" +
+				"│   └── e            9 drwxr-xr-x 2020-01-02\n" +
+				"│       └── ccccc    9 -rw-r--r-- 2020-01-02\n" +
+				"└── x                0 drwxr-xr-x 2020-01-02\n",
+		},
+		{
+			name:   "Dir Sizes Filtered",
+			config: PathConfig{Columns: columns[:1], DirSizes: true, Exclude: []string{"bb"}, PruneEmpty: true},
+			output: "" +
+				"                  SIZE\n" +
+				".                   10\n" +
+				"├── a                1\n" +
+				"└── d                9\n" +
+				"    └── e            9\n" +
+				"        └── ccccc    9\n",
+		},
+	}
+
+	for _, test := range tests {
+		info, err := os.Stat(".")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		buffer := &bytes.Buffer{}
+
+		if err := cli.RenderTreeTableView(buffer, PathWithConfig(info, ".", test.config), test.config.ColumnSpecs()...); err != nil {
+			t.Errorf("%s: %s", test.name, err)
+		} else if s := buffer.String(); s != test.output {
+			t.Errorf("%s:\n\n%s\n%q", test.name, s, s)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package tree
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/achille-roussel/cli/internal/compare"
+)
+
+// PathSort represents the orders in which the files of directories are listed.
+type PathSort int
+
+const (
+	// NameSort sorts files by name in natural order, where numbers are
+	// compared by value so "v2" comes before "v10", ignoring case.
+	NameSort PathSort = iota
+
+	// SizeSort sorts files by size, largest first.
+	SizeSort
+
+	// ModTimeSort sorts files by modification time, most recent first.
+	ModTimeSort
+
+	// ExtensionSort sorts files by extension, then by name.
+	ExtensionSort
+)
+
+// ParsePathSort parses the name of a sort order: name (or version), size,
+// mtime or extension.
+func ParsePathSort(s string) (PathSort, error) {
+	switch strings.ToLower(s) {
+	case "name", "version":
+		return NameSort, nil
+	case "size":
+		return SizeSort, nil
+	case "mtime":
+		return ModTimeSort, nil
+	case "extension", "ext":
+		return ExtensionSort, nil
+	default:
+		return NameSort, fmt.Errorf("tree.ParsePathSort: unknown sort order %q, expected name, version, size, mtime or extension", s)
+	}
+}
+
+func sortFiles(files []file, config *PathConfig) {
+	sort.SliceStable(files, func(i int, j int) bool {
+		a, b := files[i], files[j]
+
+		if config.DirsFirst {
+			if da, db := a.info.IsDir(), b.info.IsDir(); da != db {
+				return da
+			}
+		}
+
+		c := compareFiles(a, b, config)
+
+		if config.Reverse {
+			c = -c
+		}
+
+		return c < 0
+	})
+}
+
+func compareFiles(a file, b file, config *PathConfig) int {
+	switch config.SortBy {
+	case SizeSort:
+		if sa, sb := a.info.Size(), b.info.Size(); sa != sb {
+			return compare.Ordered(sa > sb, sa < sb)
+		}
+
+	case ModTimeSort:
+		if ta, tb := a.info.ModTime(), b.info.ModTime(); !ta.Equal(tb) {
+			return compare.Ordered(ta.After(tb), ta.Before(tb))
+		}
+
+	case ExtensionSort:
+		if c := compare.NaturalFold(filepath.Ext(a.name), filepath.Ext(b.name)); c != 0 {
+			return c
+		}
+	}
+
+	return compare.NaturalFold(a.name, b.name)
+}
@@ -0,0 +1,153 @@
+package tree
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/achille-roussel/cli"
+)
+
+func TestPathSort(t *testing.T) {
+	root := makeFixture(t)
+	defer os.RemoveAll(root)
+
+	now := time.Now()
+
+	for _, f := range []struct {
+		name  string
+		size  int
+		mtime time.Duration
+	}{
+		{"a.txt", 3, 1 * time.Hour},
+		{"B.go", 1, 3 * time.Hour},
+		{"c.go", 3, 2 * time.Hour},
+		{"file2", 5, 0},
+		{"File10", 4, 4 * time.Hour},
+	} {
+		path := filepath.Join(root, f.name)
+
+		if err := ioutil.WriteFile(path, []byte(strings.Repeat("x", f.size)), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.Chtimes(path, now, now.Add(f.mtime)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		config PathConfig
+		output []string
+	}{
+		{
+			name:   "Name",
+			config: PathConfig{SortBy: NameSort},
+			output: []string{"a.txt", "B.go", "c.go", "file2", "File10"},
+		},
+		{
+			name:   "Name Reverse",
+			config: PathConfig{SortBy: NameSort, Reverse: true},
+			output: []string{"File10", "file2", "c.go", "B.go", "a.txt"},
+		},
+		{
+			name:   "Size",
+			config: PathConfig{SortBy: SizeSort},
+			output: []string{"file2", "File10", "a.txt", "c.go", "B.go"},
+		},
+		{
+			name:   "Size Reverse",
+			config: PathConfig{SortBy: SizeSort, Reverse: true},
+			output: []string{"B.go", "c.go", "a.txt", "File10", "file2"},
+		},
+		{
+			name:   "Modification Time",
+			config: PathConfig{SortBy: ModTimeSort},
+			output: []string{"File10", "B.go", "c.go", "a.txt", "file2"},
+		},
+		{
+			name:   "Extension",
+			config: PathConfig{SortBy: ExtensionSort},
+			output: []string{"file2", "File10", "B.go", "c.go", "a.txt"},
+		},
+	}
+
+	for _, test := range tests {
+		if s, output := renderPath(t, root, test.config, cli.TreeOptions{}), treeLines(test.output...); s != output {
+			t.Errorf("%s:\n\n%s\n%q", test.name, s, s)
+		}
+	}
+}
+
+func TestPathSortDirsFirst(t *testing.T) {
+	root := makeFixture(t, "a", "b/", "c", "d/")
+	defer os.RemoveAll(root)
+
+	tests := []struct {
+		name   string
+		config PathConfig
+		output []string
+	}{
+		{
+			name:   "Mixed",
+			output: []string{"a", "b", "c", "d"},
+		},
+		{
+			name:   "Dirs First",
+			config: PathConfig{DirsFirst: true},
+			output: []string{"b", "d", "a", "c"},
+		},
+		{
+			name:   "Dirs First Reverse",
+			config: PathConfig{DirsFirst: true, Reverse: true},
+			output: []string{"d", "b", "c", "a"},
+		},
+	}
+
+	for _, test := range tests {
+		if s, output := renderPath(t, root, test.config, cli.TreeOptions{}), treeLines(test.output...); s != output {
+			t.Errorf("%s:\n\n%s\n%q", test.name, s, s)
+		}
+	}
+}
+
+func TestParsePathSort(t *testing.T) {
+	for s, by := range map[string]PathSort{
+		"name":      NameSort,
+		"version":   NameSort,
+		"size":      SizeSort,
+		"mtime":     ModTimeSort,
+		"extension": ExtensionSort,
+		"EXT":       ExtensionSort,
+	} {
+		if x, err := ParsePathSort(s); err != nil {
+			t.Errorf("%s: %s", s, err)
+		} else if x != by {
+			t.Errorf("%s: expected %d but found %d", s, by, x)
+		}
+	}
+
+	if _, err := ParsePathSort("color"); err == nil {
+		t.Error("expected an error when parsing an unknown sort order")
+	}
+}
+
+// treeLines returns the lines rendered for a list of leaves under the root of
+// a tree.
+func treeLines(names ...string) string {
+	s := ""
+
+	for i, name := range names {
+		if i == len(names)-1 {
+			s += "└── " + name + "\n"
+		} else {
+			s += "├── " + name + "\n"
+		}
+	}
+
+	return s
+}
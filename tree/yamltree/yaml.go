@@ -0,0 +1,105 @@
+// Package yamltree decodes YAML documents into trees rendered like the JSON
+// documents of the tree package. It lives in its own package so programs which
+// don't use YAML don't depend on a YAML parser.
+package yamltree
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/achille-roussel/cli"
+	"github.com/achille-roussel/cli/tree"
+	"gopkg.in/yaml.v3"
+)
+
+// Decoder reads YAML documents from a stream and decodes them into trees,
+// where the keys of mappings and the indexes of sequences label the nodes.
+// Keys are kept in the order they appear in the documents.
+type Decoder struct {
+	d      *yaml.Decoder
+	config tree.DocumentConfig
+}
+
+func NewDecoder(r io.Reader) *Decoder {
+	return NewDecoderWithConfig(r, tree.DefaultDocumentConfig)
+}
+
+func NewDecoderWithConfig(r io.Reader, config tree.DocumentConfig) *Decoder {
+	return &Decoder{d: yaml.NewDecoder(r), config: config}
+}
+
+// Decode returns the tree of the next document of the stream, or io.EOF when
+// all documents have been read.
+func (d *Decoder) Decode() (cli.TreeView, error) {
+	var doc yaml.Node
+
+	if err := d.d.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	return d.decode("", &doc, nil)
+}
+
+// decode converts node to a tree, aliases holds the aliases being expanded on
+// the path from the root, which is used to reject recursive documents.
+func (d *Decoder) decode(label string, node *yaml.Node, aliases []*yaml.Node) (cli.TreeView, error) {
+	var nodes []cli.TreeView
+
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return d.config.Leaf(label, tree.NullLeaf, "null"), nil
+		}
+		return d.decode(label, node.Content[0], aliases)
+
+	case yaml.AliasNode:
+		for _, alias := range aliases {
+			if alias == node {
+				return nil, fmt.Errorf("yamltree.(*Decoder).Decode: recursive alias *%s at line %d", node.Value, node.Line)
+			}
+		}
+		return d.decode(label, node.Alias, append(aliases, node))
+
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i].Value
+
+			if len(key) == 0 {
+				// Empty labels are reserved for the root of documents.
+				key = `""`
+			}
+
+			child, err := d.decode(key, node.Content[i+1], aliases)
+			if err != nil {
+				return nil, err
+			}
+
+			nodes = append(nodes, child)
+		}
+
+		return d.config.Node(label, "{}", nodes), nil
+
+	case yaml.SequenceNode:
+		for i, item := range node.Content {
+			child, err := d.decode(tree.IndexLabel(i), item, aliases)
+			if err != nil {
+				return nil, err
+			}
+
+			nodes = append(nodes, child)
+		}
+
+		return d.config.Node(label, "[]", nodes), nil
+	}
+
+	switch node.ShortTag() {
+	case "!!int", "!!float":
+		return d.config.Leaf(label, tree.NumberLeaf, node.Value), nil
+	case "!!bool":
+		return d.config.Leaf(label, tree.BoolLeaf, node.Value), nil
+	case "!!null":
+		return d.config.Leaf(label, tree.NullLeaf, "null"), nil
+	default:
+		return d.config.Leaf(label, tree.StringLeaf, node.Value), nil
+	}
+}
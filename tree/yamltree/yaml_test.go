@@ -0,0 +1,95 @@
+package yamltree
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/achille-roussel/cli"
+	"github.com/achille-roussel/cli/tree"
+)
+
+func renderDocuments(d *Decoder) (string, error) {
+	buffer := &bytes.Buffer{}
+
+	for {
+		doc, err := d.Decode()
+
+		if err == io.EOF {
+			return buffer.String(), nil
+		}
+
+		if err != nil {
+			return buffer.String(), err
+		}
+
+		if err := cli.RenderTreeView(buffer, doc); err != nil {
+			return buffer.String(), err
+		}
+	}
+}
+
+func TestDecoder(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		config tree.DocumentConfig
+		output string
+	}{
+		{
+			name:   "Leaves",
+			input:  "s: hello\nn: 4.2\nb: true\nz: ~\nq: \"1\"\n",
+			output: "{}\n├── s: \"hello\"\n├── n: 4.2\n├── b: true\n├── z: null\n└── q: \"1\"\n",
+		},
+		{
+			name:   "Styled Leaves",
+			input:  "s: a\nn: 1\nb: false\nz: null\n",
+			config: tree.DefaultDocumentConfig,
+			output: "" +
+				"{}\n" +
+				"├── \x1b[1;34ms\x1b[0m: \x1b[32m\"a\"\x1b[0m\n" +
+				"├── \x1b[1;34mn\x1b[0m: \x1b[36m1\x1b[0m\n" +
+				"├── \x1b[1;34mb\x1b[0m: \x1b[33mfalse\x1b[0m\n" +
+				"└── \x1b[1;34mz\x1b[0m: \x1b[35mnull\x1b[0m\n",
+		},
+		{
+			name:   "Sequence Indexes",
+			input:  "- 1\n- [a]\n- {}\n",
+			output: "[]\n├── [0]: 1\n├── [1]\n│   └── [0]: \"a\"\n└── [2]\n",
+		},
+		{
+			name:   "Aliases",
+			input:  "base: &base\n  x: 1\ncopy: *base\n",
+			output: "{}\n├── base\n│   └── x: 1\n└── copy\n    └── x: 1\n",
+		},
+		{
+			name:   "Multiple Documents",
+			input:  "a: 1\n---\n- true\n",
+			output: "{}\n└── a: 1\n[]\n└── [0]: true\n",
+		},
+	}
+
+	for _, test := range tests {
+		s, err := renderDocuments(NewDecoderWithConfig(strings.NewReader(test.input), test.config))
+
+		if err != nil {
+			t.Errorf("%s: %s", test.name, err)
+		} else if s != test.output {
+			t.Errorf("%s:\n\n%s\n%q", test.name, s, s)
+		}
+	}
+}
+
+func TestDecoderError(t *testing.T) {
+	for _, input := range []string{
+		"a: [1, 2\n",
+		"a: b: c\n",
+		"a: *missing\n",
+		"a: &a\n  b: *a\n",
+	} {
+		if _, err := renderDocuments(NewDecoder(strings.NewReader(input))); err == nil {
+			t.Errorf("%q: expected an error on malformed input", input)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package cli
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/achille-roussel/cli/internal/compare"
+)
+
+// NewTreeView returns a tree view of v, which may be a TreeView or any Go
+// value. Structs, maps, slices and arrays are rendered as nodes which children
+// are their fields, entries and elements. Other values are leaves rendered as
+// "key: value".
+//
+// Struct fields are named after the `tree:"name"` tag when they have one, the
+// `tree:"-"` tag hides them. Pointers, maps and slices which refer to one of
+// their parents are rendered as leaves to break cycles.
+func NewTreeView(v interface{}) TreeView {
+	if view, ok := v.(TreeView); ok {
+		return view
+	}
+
+	r := reflect.ValueOf(v)
+	node := valueTreeView{v: r}
+
+	if isTreeLeaf(indirectValue(r)) {
+		node.label = formatTreeValue(r)
+	} else if r.IsValid() {
+		node.label = r.Type().String()
+	}
+
+	return node
+}
+
+type valueTreeView struct {
+	label   string
+	v       reflect.Value
+	parents *treeParent
+}
+
+// treeParent is a linked list of the pointers, maps and slices that lead to a
+// node, used to detect cycles. Values are compared by address and type, so a
+// slice of an array is not mistaken for a pointer to the struct that holds it.
+type treeParent struct {
+	ptr  uintptr
+	typ  reflect.Type
+	next *treeParent
+}
+
+func (p *treeParent) has(v reflect.Value) bool {
+	ptr, ok := treeValuePointer(v)
+
+	if ok {
+		for ; p != nil; p = p.next {
+			if p.ptr == ptr && p.typ == v.Type() {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (p *treeParent) push(v reflect.Value) *treeParent {
+	if ptr, ok := treeValuePointer(v); ok {
+		p = &treeParent{ptr, v.Type(), p}
+	}
+	return p
+}
+
+// treeValuePointer returns the address of the value referenced by v, if v is a
+// non-nil pointer or map, or a non-empty slice.
+func treeValuePointer(v reflect.Value) (uintptr, bool) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map:
+		return v.Pointer(), !v.IsNil()
+	case reflect.Slice:
+		return v.Pointer(), v.Len() != 0
+	}
+	return 0, false
+}
+
+func (t valueTreeView) Cell() string {
+	return t.label
+}
+
+func (t valueTreeView) Nodes() []TreeView {
+	v, parents := t.v, t.parents
+
+	for {
+		if parents.has(v) {
+			return nil
+		}
+
+		parents = parents.push(v)
+
+		if v.Kind() != reflect.Ptr && v.Kind() != reflect.Interface {
+			break
+		}
+
+		if v.IsNil() {
+			return nil
+		}
+
+		v = v.Elem()
+	}
+
+	if isTreeLeaf(v) {
+		return nil
+	}
+
+	var nodes []TreeView
+
+	switch v.Kind() {
+	case reflect.Struct:
+		t := v.Type()
+
+		for i, n := 0, t.NumField(); i != n; i++ {
+			f := t.Field(i)
+
+			if len(f.PkgPath) != 0 {
+				continue
+			}
+
+			name := f.Tag.Get("tree")
+
+			switch name {
+			case "-":
+				continue
+			case "":
+				name = f.Name
+			}
+
+			nodes = append(nodes, makeValueTreeView(name, v.Field(i), parents))
+		}
+
+	case reflect.Map:
+		keys := v.MapKeys()
+		names := make([]string, len(keys))
+
+		for i, k := range keys {
+			names[i] = formatTreeValue(k)
+		}
+
+		sort.Sort(treeKeys{names, keys})
+
+		for i, k := range keys {
+			nodes = append(nodes, makeValueTreeView(names[i], v.MapIndex(k), parents))
+		}
+
+	case reflect.Slice, reflect.Array:
+		for i, n := 0, v.Len(); i != n; i++ {
+			nodes = append(nodes, makeValueTreeView("["+strconv.Itoa(i)+"]", v.Index(i), parents))
+		}
+	}
+
+	return nodes
+}
+
+func makeValueTreeView(name string, v reflect.Value, parents *treeParent) valueTreeView {
+	node := valueTreeView{label: name, v: v, parents: parents}
+	x := v
+
+	for {
+		if parents.has(x) {
+			node.label += ": <cycle>"
+			return node
+		}
+
+		if (x.Kind() != reflect.Ptr && x.Kind() != reflect.Interface) || x.IsNil() {
+			break
+		}
+
+		x = x.Elem()
+	}
+
+	if isTreeLeaf(x) {
+		node.label += ": " + formatTreeValue(v)
+	}
+
+	return node
+}
+
+// isTreeLeaf returns true if v has no children when rendered as a tree, which
+// is the case of scalar values and values which have a text representation.
+func isTreeLeaf(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+	default:
+		return true
+	}
+
+	if v.Type() == reflect.TypeOf([]byte(nil)) || lookupCellFormatter(v.Type()) != nil {
+		return true
+	}
+
+	if v.CanInterface() {
+		switch v.Interface().(type) {
+		case fmt.Stringer, encoding.TextMarshaler, time.Time:
+			return true
+		}
+	}
+
+	return false
+}
+
+func formatTreeValue(v reflect.Value) string {
+	if x := indirectValue(v); !x.IsValid() {
+		return "nil"
+	}
+
+	f := makeDefaultCellFormat()
+	s := f.format(v)
+
+	// Multi-line values would be rendered as multiple lines of the node,
+	// which is confusing when they appear after a key.
+	return strings.Replace(s, "\n", `\n`, -1)
+}
+
+type treeKeys struct {
+	names []string
+	keys  []reflect.Value
+}
+
+func (k treeKeys) Len() int {
+	return len(k.names)
+}
+
+func (k treeKeys) Less(i int, j int) bool {
+	return compare.NaturalFold(k.names[i], k.names[j]) < 0
+}
+
+func (k treeKeys) Swap(i int, j int) {
+	k.names[i], k.names[j] = k.names[j], k.names[i]
+	k.keys[i], k.keys[j] = k.keys[j], k.keys[i]
+}
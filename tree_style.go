@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"io"
+	"os"
+	"strings"
+)
+
+// TreeStyle describes the guides drawn on the left of the nodes of trees. All
+// guides of a style must have the same display width.
+type TreeStyle struct {
+	// Branch is drawn before nodes that have siblings after them, and Last
+	// before the last node of their parent.
+	Branch string
+	Last   string
+
+	// Vertical is drawn on the lines under nodes that have siblings after
+	// them, Space on the lines under the last node of their parent.
+	Vertical string
+	Space    string
+
+	// Style is applied to the guides.
+	Style StyleSet
+}
+
+var (
+	LightTree = TreeStyle{
+		Branch:   "├── ",
+		Last:     "└── ",
+		Vertical: "│   ",
+		Space:    "    ",
+	}
+
+	ASCIITree = TreeStyle{
+		Branch:   "|-- ",
+		Last:     "`-- ",
+		Vertical: "|   ",
+		Space:    "    ",
+	}
+
+	RoundedTree = TreeStyle{
+		Branch:   "├── ",
+		Last:     "╰── ",
+		Vertical: "│   ",
+		Space:    "    ",
+	}
+
+	HeavyTree = TreeStyle{
+		Branch:   "┣━━ ",
+		Last:     "┗━━ ",
+		Vertical: "┃   ",
+		Space:    "    ",
+	}
+
+	DoubleTree = TreeStyle{
+		Branch:   "╠══ ",
+		Last:     "╚══ ",
+		Vertical: "║   ",
+		Space:    "    ",
+	}
+
+	CompactTree = TreeStyle{
+		Branch:   "├ ",
+		Last:     "└ ",
+		Vertical: "│ ",
+		Space:    "  ",
+	}
+)
+
+// asciiWriter is implemented by writers which output to a terminal that may
+// not be able to display UTF-8 characters.
+type asciiWriter interface {
+	asciiOnly() bool
+}
+
+// treeStyle returns the style used to render trees to w, which falls back to
+// ASCIITree when w is a Writer created by New for a non-UTF-8 locale.
+func treeStyle(w io.Writer, style *TreeStyle) *TreeStyle {
+	if style != nil {
+		return style
+	}
+
+	if a, ok := w.(asciiWriter); ok && a.asciiOnly() {
+		return &ASCIITree
+	}
+
+	return &LightTree
+}
+
+// IsUTF8Locale returns true if the locale set by the LC_ALL, LC_CTYPE or LANG
+// environment variables uses the UTF-8 encoding. No locale is assumed to be
+// UTF-8, like most modern terminals are.
+func IsUTF8Locale() bool {
+	return isUTF8Locale(os.Getenv)
+}
+
+func isUTF8Locale(getenv func(string) string) bool {
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if locale := getenv(name); len(locale) != 0 {
+			locale = strings.ToLower(locale)
+			return strings.Contains(locale, "utf-8") || strings.Contains(locale, "utf8")
+		}
+	}
+	return true
+}
@@ -0,0 +1,162 @@
+package cli
+
+import (
+	"bytes"
+	"io"
+)
+
+// ColumnTreeView is implemented by tree nodes which have values rendered in
+// columns beside the tree by RenderTreeTableView.
+type ColumnTreeView interface {
+	TreeView
+
+	ColumnCell(col int) string
+}
+
+// RenderTreeTableView renders the tree t with the column values of its nodes
+// aligned on the right side of the tree. Columns use the same spec syntax as
+// table columns, nodes which don't implement ColumnTreeView have empty cells.
+func RenderTreeTableView(w io.Writer, t TreeView, columns ...string) (err error) {
+	return RenderTreeTableViewWithOptions(w, t, TreeOptions{}, columns...)
+}
+
+// RenderTreeTableViewWithOptions is like RenderTreeTableView but allows the
+// program to configure how the tree is laid out. The maximum width applies to
+// the whole lines, tree cells are shrunk to leave room for the columns.
+func RenderTreeTableViewWithOptions(w io.Writer, t TreeView, options TreeOptions, columns ...string) (err error) {
+	options.MaxWidth = fitWidth(w, options.MaxWidth)
+	options.Style = treeStyle(w, options.Style)
+
+	var rows []treeTableRow
+
+	widths := make([]int, len(columns))
+	aligns := make([]CellAlign, len(columns))
+	header := false
+
+	for i, c := range columns {
+		widths[i] = DisplayWidthInString(column(c).string())
+		aligns[i] = column(c).alignment()
+		header = header || widths[i] != 0
+	}
+
+	if err = walkTreeView(t, NewTreeIndentWithStyle(options.Style), &options, make(map[interface{}]bool), func(tree TreeView, indent *TreeIndent) error {
+		row := treeTableRow{
+			first: indent.String(),
+			cell:  tree.Cell(),
+			cells: make([]string, len(columns)),
+		}
+
+		indent.Clear(1, indent.Depth())
+		row.rest = indent.String()
+
+		if c, ok := tree.(cycleTreeView); ok {
+			tree = c.TreeView
+		}
+
+		if c, ok := tree.(ColumnTreeView); ok {
+			for i := range columns {
+				row.cells[i] = c.ColumnCell(i)
+
+				if n := DisplayWidthInString(row.cells[i]); n > widths[i] {
+					widths[i] = n
+				}
+			}
+		}
+
+		rows = append(rows, row)
+		return nil
+	}); err != nil {
+		return
+	}
+
+	// The columns are laid out first, the tree gets the remaining width.
+	layout := options
+
+	if layout.MaxWidth > 0 {
+		for _, n := range widths {
+			layout.MaxWidth -= n + 1
+		}
+
+		if layout.MaxWidth < 1 {
+			layout.MaxWidth = 1
+		}
+	}
+
+	lines := make([][]string, len(rows))
+	width := 0
+
+	for j, row := range rows {
+		lines[j] = layoutTreeCell(row.cell, DisplayWidthInString(row.first), &layout)
+
+		for l, line := range lines[j] {
+			indent := row.first
+
+			if l != 0 {
+				indent = row.rest
+			}
+
+			if n := DisplayWidthInString(indent) + DisplayWidthInString(line); n > width {
+				width = n
+			}
+		}
+	}
+
+	buf := &bytes.Buffer{}
+
+	if header {
+		buf.Write(makeSpaces(width))
+
+		for i, c := range columns {
+			buf.WriteByte(' ')
+			RenderCell(buf, column(c).string(), widths[i], aligns[i])
+		}
+
+		if err = writeTreeTableLine(w, buf); err != nil {
+			return
+		}
+	}
+
+	for j, row := range rows {
+		for l, line := range lines[j] {
+			indent := row.first
+
+			if l != 0 {
+				indent = row.rest
+			}
+
+			buf.WriteString(indent)
+			RenderCellLeftAlign(buf, line, width-DisplayWidthInString(indent))
+
+			for i, cell := range row.cells {
+				if l != 0 {
+					cell = ""
+				}
+				buf.WriteByte(' ')
+				RenderCell(buf, cell, widths[i], aligns[i])
+			}
+
+			if err = writeTreeTableLine(w, buf); err != nil {
+				return
+			}
+		}
+	}
+
+	return
+}
+
+type treeTableRow struct {
+	first string // indentation of the first line of the cell
+	rest  string // indentation of the other lines of the cell
+	cell  string
+	cells []string
+}
+
+// writeTreeTableLine writes the line held in buf to w, without the trailing
+// spaces, and resets buf.
+func writeTreeTableLine(w io.Writer, buf *bytes.Buffer) (err error) {
+	line := bytes.TrimRight(buf.Bytes(), " ")
+	line = append(line, '\n')
+	_, err = w.Write(line)
+	buf.Reset()
+	return
+}
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"bytes"
+	"strings"
 	"testing"
 )
 
@@ -23,3 +24,355 @@ func TestTree(t *testing.T) {
 
 	t.Logf("\n\n%s\n", b.String())
 }
+
+func TestTreeFitToTerminal(t *testing.T) {
+	w := &sizedWriter{width: 12}
+
+	RenderTreeView(w, NewTree(".",
+		NewTree("A",
+			NewTree("Hello World!")),
+		NewTree("B")),
+	)
+
+	const output = "" +
+		".\n" +
+		"├── A\n" +
+		"│   └── Hel…\n" +
+		"└── B\n"
+
+	if s := w.String(); s != output {
+		t.Errorf("\n\n%s\n%q", s, s)
+	}
+}
+
+func TestTreeStyles(t *testing.T) {
+	tree := NewTree(".",
+		NewTree("A",
+			NewTree("1"),
+			NewTree("2")),
+		NewTree("B"))
+
+	tests := []struct {
+		name   string
+		style  TreeStyle
+		output string
+	}{
+		{
+			name:  "ASCII",
+			style: ASCIITree,
+			output: "" +
+				".\n" +
+				"|-- A\n" +
+				"|   |-- 1\n" +
+				"|   `-- 2\n" +
+				"`-- B\n",
+		},
+		{
+			name:  "Rounded",
+			style: RoundedTree,
+			output: "" +
+				".\n" +
+				"├── A\n" +
+				"│   ├── 1\n" +
+				"│   ╰── 2\n" +
+				"╰── B\n",
+		},
+		{
+			name:  "Compact",
+			style: CompactTree,
+			output: "" +
+				".\n" +
+				"├ A\n" +
+				"│ ├ 1\n" +
+				"│ └ 2\n" +
+				"└ B\n",
+		},
+		{
+			name:  "Styled",
+			style: TreeStyle{Branch: "+ ", Last: "+ ", Vertical: "| ", Space: "  ", Style: Style(Blue)},
+			output: "" +
+				".\n" +
+				"\033[34m+ \033[0mA\n" +
+				"\033[34m| + \033[0m1\n" +
+				"\033[34m| + \033[0m2\n" +
+				"\033[34m+ \033[0mB\n",
+		},
+	}
+
+	b := &bytes.Buffer{}
+
+	for _, test := range tests {
+		b.Reset()
+
+		if err := RenderTreeViewWithOptions(b, tree, TreeOptions{MaxWidth: -1, Style: &test.style}); err != nil {
+			t.Errorf("%s: %s", test.name, err)
+		} else if s := b.String(); s != test.output {
+			t.Errorf("%s:\n\n%s\n%q", test.name, s, s)
+		}
+	}
+}
+
+func TestTreeASCIIFallback(t *testing.T) {
+	b := &sizedWriter{}
+
+	RenderTreeView(readWriter{Writer: b, ascii: true}, NewTree(".", NewTree("A"), NewTree("B")))
+
+	if s := b.String(); s != ".\n|-- A\n`-- B\n" {
+		t.Errorf("\n\n%s\n%q", s, s)
+	}
+}
+
+func TestIsUTF8Locale(t *testing.T) {
+	tests := []struct {
+		env  map[string]string
+		utf8 bool
+	}{
+		{env: map[string]string{}, utf8: true},
+		{env: map[string]string{"LANG": "en_US.UTF-8"}, utf8: true},
+		{env: map[string]string{"LANG": "C.utf8"}, utf8: true},
+		{env: map[string]string{"LANG": "C"}, utf8: false},
+		{env: map[string]string{"LANG": "en_US.UTF-8", "LC_ALL": "POSIX"}, utf8: false},
+		{env: map[string]string{"LANG": "C", "LC_CTYPE": "fr_FR.UTF-8"}, utf8: true},
+	}
+
+	for _, test := range tests {
+		if utf8 := isUTF8Locale(func(k string) string { return test.env[k] }); utf8 != test.utf8 {
+			t.Errorf("%v: expected %t but found %t", test.env, test.utf8, utf8)
+		}
+	}
+}
+
+func TestTreeLimits(t *testing.T) {
+	tree := NewTree(".",
+		NewTree("A",
+			NewTree("1",
+				NewTree("x"))),
+		NewTree("B"),
+		NewTree(".hidden"),
+		NewTree("C"),
+		NewTree("D"))
+
+	tests := []struct {
+		name    string
+		options TreeOptions
+		output  string
+	}{
+		{
+			name:    "Max Depth",
+			options: TreeOptions{MaxDepth: 1},
+			output: "" +
+				".\n" +
+				"├── A\n" +
+				"├── B\n" +
+				"├── .hidden\n" +
+				"├── C\n" +
+				"└── D\n",
+		},
+		{
+			name:    "Root Only",
+			options: TreeOptions{MaxDepth: -1},
+			output:  ".\n",
+		},
+		{
+			name:    "Max Children",
+			options: TreeOptions{MaxDepth: 1, MaxChildren: 2},
+			output: "" +
+				".\n" +
+				"├── A\n" +
+				"├── B\n" +
+				"└── … 3 more\n",
+		},
+		{
+			name: "Filter",
+			options: TreeOptions{Filter: func(node TreeView) bool {
+				return !strings.HasPrefix(node.Cell(), ".")
+			}},
+			output: "" +
+				".\n" +
+				"├── A\n" +
+				"│   └── 1\n" +
+				"│       └── x\n" +
+				"├── B\n" +
+				"├── C\n" +
+				"└── D\n",
+		},
+	}
+
+	b := &bytes.Buffer{}
+
+	for _, test := range tests {
+		b.Reset()
+		test.options.MaxWidth = -1
+
+		if err := RenderTreeViewWithOptions(b, tree, test.options); err != nil {
+			t.Errorf("%s: %s", test.name, err)
+		} else if s := b.String(); s != test.output {
+			t.Errorf("%s:\n\n%s\n%q", test.name, s, s)
+		}
+	}
+}
+
+func TestTreeCycle(t *testing.T) {
+	a := NewTree("A")
+	b := NewTree("B", a)
+	a.nodes = append(a.nodes, b, NewTree("C"))
+
+	w := &bytes.Buffer{}
+	RenderTreeView(w, a)
+
+	const output = "" +
+		"A\n" +
+		"├── B\n" +
+		"│   └── A <cycle>\n" +
+		"└── C\n"
+
+	if s := w.String(); s != output {
+		t.Errorf("\n\n%s\n%q", s, s)
+	}
+}
+
+func TestNewTreeView(t *testing.T) {
+	type Node struct {
+		Name   string `tree:"name"`
+		Secret string `tree:"-"`
+		Next   *Node
+		Tags   []string
+		Attrs  map[string]int
+	}
+
+	a := &Node{Name: "a", Tags: []string{"x", "y"}, Attrs: map[string]int{"k10": 10, "k2": 2}}
+	b := &Node{Name: "b", Next: a}
+	a.Next = b
+
+	w := &bytes.Buffer{}
+	RenderTreeView(w, NewTreeView(a))
+
+	const output = "" +
+		"*cli.Node\n" +
+		"├── name: a\n" +
+		"├── Next\n" +
+		"│   ├── name: b\n" +
+		"│   ├── Next: <cycle>\n" +
+		"│   ├── Tags\n" +
+		"│   └── Attrs\n" +
+		"├── Tags\n" +
+		"│   ├── [0]: x\n" +
+		"│   └── [1]: y\n" +
+		"└── Attrs\n" +
+		"    ├── k2: 2\n" +
+		"    └── k10: 10\n"
+
+	if s := w.String(); s != output {
+		t.Errorf("\n\n%s\n%q", s, s)
+	}
+}
+
+func TestNewTreeViewContainerCycles(t *testing.T) {
+	m := map[string]interface{}{"name": "m"}
+	m["self"] = m
+
+	l := []interface{}{"l", nil}
+	l[1] = l
+
+	w := &bytes.Buffer{}
+	RenderTreeView(w, NewTreeView([]interface{}{m, l}))
+
+	const output = "" +
+		"[]interface {}\n" +
+		"├── [0]\n" +
+		"│   ├── name: m\n" +
+		"│   └── self: <cycle>\n" +
+		"└── [1]\n" +
+		"    ├── [0]: l\n" +
+		"    └── [1]: <cycle>\n"
+
+	if s := w.String(); s != output {
+		t.Errorf("\n\n%s\n%q", s, s)
+	}
+}
+
+type testColumnTree struct {
+	cell  string
+	cells []string
+	nodes []TreeView
+}
+
+func (t testColumnTree) Cell() string              { return t.cell }
+func (t testColumnTree) Nodes() []TreeView         { return t.nodes }
+func (t testColumnTree) ColumnCell(col int) string { return t.cells[col] }
+
+func TestRenderTreeTableView(t *testing.T) {
+	tree := testColumnTree{".", []string{"1.2K", "drwxr-xr-x"}, []TreeView{
+		testColumnTree{"src", []string{"1K", "drwxr-xr-x"}, []TreeView{
+			testColumnTree{"main.go", []string{"1000", "-rw-r--r--"}, nil},
+			NewTree("README"),
+		}},
+		testColumnTree{"LICENSE", []string{"24", "-rw-r--r--"}, nil},
+	}}
+
+	tests := []struct {
+		name    string
+		options TreeOptions
+		columns []string
+		output  string
+	}{
+		{
+			name:    "Columns",
+			options: TreeOptions{MaxWidth: -1},
+			columns: []string{":SIZE", "MODE:"},
+			output: "" +
+				"                SIZE MODE\n" +
+				".               1.2K drwxr-xr-x\n" +
+				"├── src           1K drwxr-xr-x\n" +
+				"│   ├── main.go 1000 -rw-r--r--\n" +
+				"│   └── README\n" +
+				"└── LICENSE       24 -rw-r--r--\n",
+		},
+		{
+			name:    "Max Width",
+			options: TreeOptions{MaxWidth: 28},
+			columns: []string{":SIZE", "MODE:"},
+			output: "" +
+				"             SIZE MODE\n" +
+				".            1.2K drwxr-xr-x\n" +
+				"├── src        1K drwxr-xr-x\n" +
+				"│   ├── mai… 1000 -rw-r--r--\n" +
+				"│   └── REA…\n" +
+				"└── LICENSE    24 -rw-r--r--\n",
+		},
+	}
+
+	b := &bytes.Buffer{}
+
+	for _, test := range tests {
+		b.Reset()
+
+		if err := RenderTreeTableViewWithOptions(b, tree, test.options, test.columns...); err != nil {
+			t.Errorf("%s: %s", test.name, err)
+		} else if s := b.String(); s != test.output {
+			t.Errorf("%s:\n\n%s\n%q", test.name, s, s)
+		}
+	}
+}
+
+func TestRenderTreeTableViewCycle(t *testing.T) {
+	a := &testColumnTree{cell: "a", cells: []string{"1"}}
+	b := &testColumnTree{cell: "b", cells: []string{"2"}, nodes: []TreeView{a}}
+	a.nodes = []TreeView{b}
+
+	w := &bytes.Buffer{}
+
+	if err := RenderTreeTableViewWithOptions(w, a, TreeOptions{MaxWidth: -1}, ":N"); err != nil {
+		t.Fatal(err)
+	}
+
+	const output = "" +
+		"                  N\n" +
+		"a                 1\n" +
+		"└── b             2\n" +
+		"    └── a <cycle> 1\n"
+
+	if s := w.String(); s != output {
+		t.Errorf("\n\n%s\n%q", s, s)
+	}
+}
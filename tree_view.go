@@ -1,7 +1,9 @@
 package cli
 
 import (
+	"fmt"
 	"io"
+	"reflect"
 	"strings"
 )
 
@@ -11,29 +13,119 @@ type TreeView interface {
 	Nodes() []TreeView
 }
 
+// KeyedTreeView is implemented by tree views which can tell when two of their
+// nodes are the same, which is used to detect cycles when rendering graphs.
+// Keys must be comparable values. Nodes which are pointers are compared by
+// address when they don't implement this interface.
+//
+// Nodes which are one of their own parents are rendered with a "<cycle>"
+// marker, like the pointers of NewTreeView, and their children are not
+// rendered.
+type KeyedTreeView interface {
+	TreeView
+
+	Key() interface{}
+}
+
+func treeViewKey(t TreeView) (interface{}, bool) {
+	if k, ok := t.(KeyedTreeView); ok {
+		return k.Key(), true
+	}
+
+	if reflect.TypeOf(t).Kind() == reflect.Ptr {
+		return t, true
+	}
+
+	return nil, false
+}
+
+// TreeOptions configures the rendering of trees.
+type TreeOptions struct {
+	// MaxWidth is the maximum width of the tree lines. Zero means the width of
+	// the terminal when writing to a terminal Writer, a negative value means
+	// no limit.
+	MaxWidth int
+
+	// Overflow defines how lines wider than MaxWidth are rendered.
+	Overflow Overflow
+
+	// Style defines the guides drawn on the left of nodes. When nil, trees
+	// are rendered with LightTree, or ASCIITree when writing to a Writer
+	// which doesn't support UTF-8.
+	Style *TreeStyle
+
+	// MaxDepth is the depth of the deepest nodes rendered, the root being at
+	// depth zero. The children of nodes at the maximum depth are not loaded.
+	// Zero means no limit, a negative value renders the root only.
+	MaxDepth int
+
+	// MaxChildren is the maximum number of children rendered under a node,
+	// the others are replaced with a "… N more" line. Zero means no limit.
+	MaxChildren int
+
+	// Filter returns whether a node and its children are rendered, it is not
+	// called for the root of the tree. Nil means all nodes are rendered.
+	Filter func(TreeView) bool
+}
+
 func RenderTreeView(w io.Writer, t TreeView) (err error) {
-	return renderTreeView(w, t, NewTreeIndent())
+	return RenderTreeViewWithOptions(w, t, TreeOptions{})
 }
 
-func renderTreeView(w io.Writer, tree TreeView, indent *TreeIndent) (err error) {
-	nodes := tree.Nodes()
-	lines := strings.Split(tree.Cell(), "\n")
+// RenderTreeViewWithOptions is like RenderTreeView but allows the program to
+// configure how the tree is laid out.
+func RenderTreeViewWithOptions(w io.Writer, t TreeView, options TreeOptions) (err error) {
+	options.MaxWidth = fitWidth(w, options.MaxWidth)
+	options.Style = treeStyle(w, options.Style)
+	return walkTreeView(t, NewTreeIndentWithStyle(options.Style), &options, make(map[interface{}]bool), func(tree TreeView, indent *TreeIndent) (err error) {
+		lines := layoutTreeCell(tree.Cell(), indent.Width(), &options)
 
-	for index, line := range lines {
-		indent.Clear(index, indent.Depth())
+		for index, line := range lines {
+			indent.Clear(index, indent.Depth())
 
-		if err = renderTreeLine(w, line, indent.String()); err != nil {
-			return
+			if err = renderTreeLine(w, line, indent.String()); err != nil {
+				return
+			}
 		}
+
+		return
+	})
+}
+
+// walkTreeView calls visit for tree and its children, with indent set for each
+// node. parents holds the keys of the nodes on the path from the root which are
+// used to detect cycles, nodes which are one of their own parents are visited
+// as a cycleTreeView.
+func walkTreeView(tree TreeView, indent *TreeIndent, options *TreeOptions, parents map[interface{}]bool, visit func(TreeView, *TreeIndent) error) (err error) {
+	key, keyed := treeViewKey(tree)
+
+	if keyed && parents[key] {
+		// The node is one of its own parents, rendering its children would
+		// never end.
+		return visit(cycleTreeView{tree}, indent)
+	}
+
+	if err = visit(tree, indent); err != nil {
+		return
+	}
+
+	if options.MaxDepth < 0 || (options.MaxDepth > 0 && indent.Depth() >= options.MaxDepth) {
+		return
+	}
+
+	if keyed {
+		parents[key] = true
+		defer delete(parents, key)
 	}
 
+	nodes := filterTreeNodes(tree.Nodes(), options)
 	depth := indent.Push()
 	count := len(nodes)
 
 	for index, node := range nodes {
 		indent.Next(index, count, depth)
 
-		if err = renderTreeView(w, node, indent); err != nil {
+		if err = walkTreeView(node, indent, options, parents, visit); err != nil {
 			return
 		}
 	}
@@ -42,6 +134,65 @@ func renderTreeView(w io.Writer, tree TreeView, indent *TreeIndent) (err error)
 	return
 }
 
+// cycleTreeView wraps a node which is one of its own parents, it is rendered
+// with a "<cycle>" marker and has no children.
+type cycleTreeView struct {
+	TreeView
+}
+
+func (t cycleTreeView) Cell() string {
+	return t.TreeView.Cell() + " <cycle>"
+}
+
+func (t cycleTreeView) Nodes() []TreeView {
+	return nil
+}
+
+func filterTreeNodes(nodes []TreeView, options *TreeOptions) []TreeView {
+	if options.Filter != nil {
+		filtered := nodes[:0:0]
+
+		for _, node := range nodes {
+			if options.Filter(node) {
+				filtered = append(filtered, node)
+			}
+		}
+
+		nodes = filtered
+	}
+
+	if max := options.MaxChildren; max > 0 && len(nodes) > max {
+		more := NewTree(fmt.Sprintf("%s %d more", Ellipsis, len(nodes)-max))
+		nodes = append(nodes[:max:max], more)
+	}
+
+	return nodes
+}
+
+// layoutTreeCell splits cell in lines which fit in the maximum width of the
+// tree once indented by indent columns.
+func layoutTreeCell(cell string, indent int, options *TreeOptions) []string {
+	lines := strings.Split(cell, "\n")
+
+	if options.MaxWidth <= 0 {
+		return lines
+	}
+
+	width := options.MaxWidth - indent
+
+	if width < 1 {
+		width = 1
+	}
+
+	layout := make([]string, 0, len(lines))
+
+	for _, line := range lines {
+		layout = append(layout, layoutCell(line, width, options.Overflow)...)
+	}
+
+	return layout
+}
+
 func renderTreeLine(w io.Writer, line string, indent string) (err error) {
 	if _, err = io.WriteString(w, indent); err == nil {
 		if _, err = io.WriteString(w, line); err == nil {
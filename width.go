@@ -0,0 +1,234 @@
+package cli
+
+import "unicode"
+
+// RuneWidth returns the number of columns that r occupies when displayed on a
+// terminal.
+//
+// Control characters, combining marks and other zero-width code points have a
+// width of zero, East Asian wide and fullwidth characters (which includes most
+// emoji) have a width of two, every other character has a width of one.
+//
+// RuneWidth looks at r in isolation, use DisplayWidth to measure sequences of
+// runes that may form grapheme clusters.
+func RuneWidth(r rune) int {
+	switch {
+	case r < 0x20, r >= 0x7F && r < 0xA0:
+		return 0
+	case r < 0x300:
+		return 1
+	case isZeroWidth(r):
+		return 0
+	case unicode.Is(wide, r):
+		return 2
+	default:
+		return 1
+	}
+}
+
+func isZeroWidth(r rune) bool {
+	switch {
+	case r >= 0x1160 && r <= 0x11FF:
+		// Hangul Jamo medial vowels and final consonants combine with the
+		// leading consonant to form a single syllable.
+		return true
+	case r == 0xAD:
+		// The soft hyphen is a format character but is rendered by terminals.
+		return false
+	}
+	return unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf)
+}
+
+const (
+	zeroWidthJoiner        = 0x200D
+	textPresentation       = 0xFE0E
+	emojiPresentation      = 0xFE0F
+	emojiModifierFirst     = 0x1F3FB
+	emojiModifierLast      = 0x1F3FF
+	regionalIndicatorFirst = 0x1F1E6
+	regionalIndicatorLast  = 0x1F1FF
+)
+
+// displayWidth accumulates the display width of a sequence of runes, taking
+// grapheme clusters into account so that emoji sequences, flags and characters
+// followed by variation selectors are measured the way terminals render them.
+type displayWidth struct {
+	n    int  // accumulated width
+	last int  // width of the last grapheme cluster
+	join bool // the last rune was a zero-width joiner
+	flag bool // the last rune was an unpaired regional indicator
+}
+
+func (d *displayWidth) add(r rune) {
+	switch {
+	case r == zeroWidthJoiner:
+		d.join = d.last != 0
+		return
+
+	case d.join:
+		// The rune is joined to the previous grapheme cluster, which already
+		// accounts for its width.
+		d.join = false
+		return
+
+	case r == emojiPresentation:
+		if d.last == 1 {
+			d.n++
+			d.last = 2
+		}
+		return
+
+	case r == textPresentation:
+		return
+
+	case r >= emojiModifierFirst && r <= emojiModifierLast && d.last == 2:
+		return
+
+	case r >= regionalIndicatorFirst && r <= regionalIndicatorLast:
+		// Pairs of regional indicators form a flag which is rendered as a
+		// single wide character.
+		if d.flag = !d.flag; d.flag {
+			d.n += 2
+			d.last = 2
+		}
+		return
+	}
+
+	d.flag = false
+
+	if w := RuneWidth(r); w != 0 {
+		d.n += w
+		d.last = w
+	}
+}
+
+// Wide and fullwidth characters, generated from the Unicode 17.0.0 East Asian
+// Width property (values W and F).
+var wide = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{0x1100, 0x115F, 1},
+		{0x231A, 0x231B, 1},
+		{0x2329, 0x232A, 1},
+		{0x23E9, 0x23EC, 1},
+		{0x23F0, 0x23F0, 1},
+		{0x23F3, 0x23F3, 1},
+		{0x25FD, 0x25FE, 1},
+		{0x2614, 0x2615, 1},
+		{0x2630, 0x2637, 1},
+		{0x2648, 0x2653, 1},
+		{0x267F, 0x267F, 1},
+		{0x268A, 0x268F, 1},
+		{0x2693, 0x2693, 1},
+		{0x26A1, 0x26A1, 1},
+		{0x26AA, 0x26AB, 1},
+		{0x26BD, 0x26BE, 1},
+		{0x26C4, 0x26C5, 1},
+		{0x26CE, 0x26CE, 1},
+		{0x26D4, 0x26D4, 1},
+		{0x26EA, 0x26EA, 1},
+		{0x26F2, 0x26F3, 1},
+		{0x26F5, 0x26F5, 1},
+		{0x26FA, 0x26FA, 1},
+		{0x26FD, 0x26FD, 1},
+		{0x2705, 0x2705, 1},
+		{0x270A, 0x270B, 1},
+		{0x2728, 0x2728, 1},
+		{0x274C, 0x274C, 1},
+		{0x274E, 0x274E, 1},
+		{0x2753, 0x2755, 1},
+		{0x2757, 0x2757, 1},
+		{0x2795, 0x2797, 1},
+		{0x27B0, 0x27B0, 1},
+		{0x27BF, 0x27BF, 1},
+		{0x2B1B, 0x2B1C, 1},
+		{0x2B50, 0x2B50, 1},
+		{0x2B55, 0x2B55, 1},
+		{0x2E80, 0x2E99, 1},
+		{0x2E9B, 0x2EF3, 1},
+		{0x2F00, 0x2FD5, 1},
+		{0x2FF0, 0x303E, 1},
+		{0x3041, 0x3096, 1},
+		{0x3099, 0x30FF, 1},
+		{0x3105, 0x312F, 1},
+		{0x3131, 0x318E, 1},
+		{0x3190, 0x31E5, 1},
+		{0x31EF, 0x321E, 1},
+		{0x3220, 0x3247, 1},
+		{0x3250, 0xA48C, 1},
+		{0xA490, 0xA4C6, 1},
+		{0xA960, 0xA97C, 1},
+		{0xAC00, 0xD7A3, 1},
+		{0xF900, 0xFAFF, 1},
+		{0xFE10, 0xFE19, 1},
+		{0xFE30, 0xFE52, 1},
+		{0xFE54, 0xFE66, 1},
+		{0xFE68, 0xFE6B, 1},
+		{0xFF01, 0xFF60, 1},
+		{0xFFE0, 0xFFE6, 1},
+	},
+	R32: []unicode.Range32{
+		{0x16FE0, 0x16FE4, 1},
+		{0x16FF0, 0x16FF6, 1},
+		{0x17000, 0x18CD5, 1},
+		{0x18CFF, 0x18D1E, 1},
+		{0x18D80, 0x18DF2, 1},
+		{0x1AFF0, 0x1AFF3, 1},
+		{0x1AFF5, 0x1AFFB, 1},
+		{0x1AFFD, 0x1AFFE, 1},
+		{0x1B000, 0x1B122, 1},
+		{0x1B132, 0x1B132, 1},
+		{0x1B150, 0x1B152, 1},
+		{0x1B155, 0x1B155, 1},
+		{0x1B164, 0x1B167, 1},
+		{0x1B170, 0x1B2FB, 1},
+		{0x1D300, 0x1D356, 1},
+		{0x1D360, 0x1D376, 1},
+		{0x1F004, 0x1F004, 1},
+		{0x1F0CF, 0x1F0CF, 1},
+		{0x1F18E, 0x1F18E, 1},
+		{0x1F191, 0x1F19A, 1},
+		{0x1F200, 0x1F202, 1},
+		{0x1F210, 0x1F23B, 1},
+		{0x1F240, 0x1F248, 1},
+		{0x1F250, 0x1F251, 1},
+		{0x1F260, 0x1F265, 1},
+		{0x1F300, 0x1F320, 1},
+		{0x1F32D, 0x1F335, 1},
+		{0x1F337, 0x1F37C, 1},
+		{0x1F37E, 0x1F393, 1},
+		{0x1F3A0, 0x1F3CA, 1},
+		{0x1F3CF, 0x1F3D3, 1},
+		{0x1F3E0, 0x1F3F0, 1},
+		{0x1F3F4, 0x1F3F4, 1},
+		{0x1F3F8, 0x1F43E, 1},
+		{0x1F440, 0x1F440, 1},
+		{0x1F442, 0x1F4FC, 1},
+		{0x1F4FF, 0x1F53D, 1},
+		{0x1F54B, 0x1F54E, 1},
+		{0x1F550, 0x1F567, 1},
+		{0x1F57A, 0x1F57A, 1},
+		{0x1F595, 0x1F596, 1},
+		{0x1F5A4, 0x1F5A4, 1},
+		{0x1F5FB, 0x1F64F, 1},
+		{0x1F680, 0x1F6C5, 1},
+		{0x1F6CC, 0x1F6CC, 1},
+		{0x1F6D0, 0x1F6D2, 1},
+		{0x1F6D5, 0x1F6D8, 1},
+		{0x1F6DC, 0x1F6DF, 1},
+		{0x1F6EB, 0x1F6EC, 1},
+		{0x1F6F4, 0x1F6FC, 1},
+		{0x1F7E0, 0x1F7EB, 1},
+		{0x1F7F0, 0x1F7F0, 1},
+		{0x1F90C, 0x1F93A, 1},
+		{0x1F93C, 0x1F945, 1},
+		{0x1F947, 0x1F9FF, 1},
+		{0x1FA70, 0x1FA7C, 1},
+		{0x1FA80, 0x1FA8A, 1},
+		{0x1FA8E, 0x1FAC6, 1},
+		{0x1FAC8, 0x1FAC8, 1},
+		{0x1FACD, 0x1FADC, 1},
+		{0x1FADF, 0x1FAEA, 1},
+		{0x1FAEF, 0x1FAF8, 1},
+		{0x20000, 0x3FFFF, 1},
+	},
+}
@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDisplayWidth(t *testing.T) {
+	tests := []struct {
+		in    string
+		width int
+	}{
+		{"", 0},
+		{"Hello World!", 12},
+		{"\033[1mHello\033[0m", 5},
+		{"日本語", 6},
+		{"ｈｅｌｌｏ", 10},
+		{"한국어", 6},
+		{"e\u0301", 1},            // combining acute accent
+		{"\u1100\u1161\u11A8", 2}, // decomposed hangul syllable
+		{"🙂", 2},
+		{"\u2764\uFE0F", 2},         // emoji presentation selector
+		{"\u2764\uFE0E", 1},         // text presentation selector
+		{"\U0001F44D\U0001F3FD", 2}, // skin tone modifier
+		{"\U0001F468\u200D\U0001F469\u200D\U0001F467", 2}, // zero-width joiner sequence
+		{"\U0001F1EB\U0001F1F7\U0001F1EF\U0001F1F5", 4},   // flags
+		{"a\u200Bb", 2}, // zero-width space
+	}
+
+	for _, test := range tests {
+		if width := DisplayWidthInString(test.in); width != test.width {
+			t.Errorf("%q: expected width %d but found %d", test.in, test.width, width)
+		}
+	}
+}
+
+func TestRenderCellWideCharacters(t *testing.T) {
+	tests := []struct {
+		cell  string
+		align CellAlign
+		out   string
+	}{
+		{"日本", LeftAlign, "日本  "},
+		{"日本", RightAlign, "  日本"},
+		{"日本", Centered, " 日本 "},
+		{"🙂", LeftAlign, "🙂    "},
+	}
+
+	buffer := &bytes.Buffer{}
+
+	for _, test := range tests {
+		buffer.Reset()
+
+		if err := RenderCell(buffer, test.cell, 6, test.align); err != nil {
+			t.Error(err)
+		} else if s := buffer.String(); s != test.out {
+			t.Errorf("%q: expected %q but found %q", test.cell, test.out, s)
+		}
+	}
+}
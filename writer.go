@@ -5,6 +5,7 @@ import (
 	"io"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 
 	"golang.org/x/crypto/ssh/terminal"
@@ -16,14 +17,100 @@ type Writer interface {
 	io.Writer
 
 	Flush() error
+
+	// Size returns the dimensions of the terminal that the writer outputs to,
+	// or zero if the writer isn't connected to a terminal.
+	Size() (width int, height int)
 }
 
-func newWriter(term *terminal.Terminal, output *os.File) (writer Writer, err error) {
+// fitWidth returns max if it is not zero, otherwise the width of the terminal
+// that w outputs to, if any.
+func fitWidth(w io.Writer, max int) int {
+	if max == 0 {
+		if t, ok := w.(Writer); ok {
+			max, _ = t.Size()
+		}
+	}
+	return max
+}
+
+func newWriter(term *terminal.Terminal, output *os.File, profile ColorProfile) (writer Writer, err error) {
 	if !terminal.IsTerminal(int(output.Fd())) {
-		writer = newFileWriter(output)
+		writer = newFileWriter(output, profile)
 		return
 	}
-	return newTermWriter(term, output)
+
+	if writer, err = newTermWriter(term, output); err == nil && profile != TrueColor {
+		writer = NewColorWriter(writer, profile)
+	}
+
+	return
+}
+
+// NewColorWriter returns a Writer which converts the styles written to w to
+// the closest ones supported by the given color profile.
+func NewColorWriter(w Writer, profile ColorProfile) Writer {
+	return &colorWriter{Writer: w, p: profile}
+}
+
+type colorWriter struct {
+	Writer
+	p ColorProfile
+
+	// pending holds the start of an escape sequence which was cut at the end
+	// of the last write, it is converted with the bytes of the next one.
+	mutex   sync.Mutex
+	pending []byte
+}
+
+func (w *colorWriter) Write(b []byte) (n int, err error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if len(w.pending) == 0 && bytes.IndexByte(b, esc) < 0 {
+		return w.Writer.Write(b)
+	}
+
+	buf := append(append([]byte(nil), w.pending...), b...)
+	cut := truncatedSequence(buf)
+	w.pending = buf[cut:]
+
+	if cut != 0 {
+		_, err = w.Writer.Write(ConvertStyles(buf[:cut], w.p))
+	}
+
+	if err == nil {
+		n = len(b)
+	}
+
+	return
+}
+
+// Flush writes the bytes of incomplete escape sequences, if any, then flushes
+// the underlying writer.
+func (w *colorWriter) Flush() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if b := w.pending; len(b) != 0 {
+		w.pending = nil
+
+		if _, err := w.Writer.Write(ConvertStyles(b, w.p)); err != nil {
+			return err
+		}
+	}
+
+	return w.Writer.Flush()
+}
+
+func (w *colorWriter) Close() error {
+	err := w.Flush()
+
+	if cerr := w.Writer.Close(); err == nil {
+		err = cerr
+	}
+
+	return err
 }
 
 type termWriter struct {
@@ -31,6 +118,26 @@ type termWriter struct {
 	s *terminal.State
 	f *os.File
 	c chan os.Signal
+	z *termSize
+}
+
+type termSize struct {
+	mutex  sync.Mutex
+	width  int
+	height int
+}
+
+func (z *termSize) get() (w int, h int) {
+	z.mutex.Lock()
+	w, h = z.width, z.height
+	z.mutex.Unlock()
+	return
+}
+
+func (z *termSize) set(w int, h int) {
+	z.mutex.Lock()
+	z.width, z.height = w, h
+	z.mutex.Unlock()
 }
 
 func newTermWriter(t *terminal.Terminal, f *os.File) (writer Writer, err error) {
@@ -51,13 +158,15 @@ func newTermWriter(t *terminal.Terminal, f *os.File) (writer Writer, err error)
 		return
 	}
 
+	size := &termSize{width: w, height: h}
 	sigchan := make(chan os.Signal, 1)
 	signal.Notify(sigchan, syscall.SIGWINCH)
 
 	go func() {
-		for _ = range sigchan {
-			if w, h, err := terminal.GetSize(0); err != nil {
+		for range sigchan {
+			if w, h, err := terminal.GetSize(fd); err == nil {
 				t.SetSize(w, h)
+				size.set(w, h)
 			}
 		}
 	}()
@@ -67,6 +176,7 @@ func newTermWriter(t *terminal.Terminal, f *os.File) (writer Writer, err error)
 		s: s,
 		f: f,
 		c: sigchan,
+		z: size,
 	}
 	return
 }
@@ -117,17 +227,23 @@ func (w termWriter) Flush() error {
 	return nil
 }
 
+func (w termWriter) Size() (int, int) {
+	return w.z.get()
+}
+
 type fileWriter struct {
 	b *bytes.Buffer
 	f *os.File
+	p ColorProfile
 }
 
-func newFileWriter(f *os.File) Writer {
+func newFileWriter(f *os.File, p ColorProfile) Writer {
 	b := &bytes.Buffer{}
 	b.Grow(4096)
 	return fileWriter{
 		b: b,
 		f: f,
+		p: p,
 	}
 }
 
@@ -150,10 +266,18 @@ func (w fileWriter) Write(b []byte) (n int, err error) {
 }
 
 func (w fileWriter) Flush() (err error) {
-	_, err = io.Copy(w.f, w.b)
+	// The last line may have no line break yet, it still goes through write so
+	// its escape sequences are converted to the color profile.
+	if w.b.Len() != 0 {
+		err = w.write(w.b.Next(w.b.Len()))
+	}
 	return
 }
 
+func (w fileWriter) Size() (int, int) {
+	return 0, 0
+}
+
 func (w fileWriter) flushLine() (ok bool, err error) {
 	b := w.b.Bytes()
 
@@ -167,8 +291,8 @@ func (w fileWriter) flushLine() (ok bool, err error) {
 }
 
 func (w fileWriter) write(b []byte) (err error) {
-	if off := bytes.IndexByte(b, '\033'); off >= 0 {
-		b = StripStyles(b)
+	if off := bytes.IndexByte(b, esc); off >= 0 {
+		b = ConvertStyles(b, w.p)
 	}
 	_, err = w.f.Write(b)
 	return
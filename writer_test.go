@@ -0,0 +1,31 @@
+package cli
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestFileWriterFlushPartialLine(t *testing.T) {
+	f, err := ioutil.TempFile("", "cli-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+
+	w := newFileWriter(f, NoColor)
+	w.Write([]byte(Red.S("line") + "\n" + Red.S("tail")))
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := ioutil.ReadFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s := string(b); s != "line\ntail" {
+		t.Errorf("expected %q but found %q", "line\ntail", s)
+	}
+}